@@ -0,0 +1,94 @@
+package future
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Awaitable is anything that signals completion by closing a done channel. Futures (including the ones returned by FromChan), contexts and the adapters returned by FromSignal, FromWaitGroup and FromWait are all awaitables.
+type Awaitable interface {
+	// Done returns channel that will be closed on completion.
+	Done() <-chan struct{}
+}
+
+type signalChan <-chan struct{}
+
+func (ch signalChan) Done() <-chan struct{} {
+	return ch
+}
+
+// FromSignal returns an awaitable that is done once the given signal channel (like the ones returned by Done methods) is closed. The channel itself serves as the done channel, so no goroutine is started and nothing is ever received from it.
+func FromSignal(ch <-chan struct{}) Awaitable {
+	return signalChan(ch)
+}
+
+// FromWaitGroup returns an awaitable that is done once the counter of the given wait group drops to zero. The wait group is waited on by a goroutine started on the first call to Done.
+func FromWaitGroup(wg *sync.WaitGroup) Awaitable {
+	return &waitAwaitable{wait: wg.Wait}
+}
+
+// FromWait returns an awaitable that is done once the given function returns. The function is called by a goroutine started on the first call to Done. It is useful for adapting any kind of blocking wait, like the Wait method of errgroup.Group:
+//
+//	future.FromWait(func() { _ = eg.Wait() })
+func FromWait(wait func()) Awaitable {
+	return &waitAwaitable{wait: wait}
+}
+
+type waitAwaitable struct {
+	wait func()
+	once sync.Once
+	done chan struct{}
+}
+
+func (a *waitAwaitable) Done() <-chan struct{} {
+	a.once.Do(func() {
+		a.done = make(chan struct{})
+		go func() {
+			a.wait()
+			close(a.done)
+		}()
+	})
+	return a.done
+}
+
+// FromContext returns an awaitable that is done once the given context is cancelled. Contexts are awaitables already, so the context itself is returned - the function only exists for clarity at call sites.
+func FromContext(ctx context.Context) Awaitable {
+	return ctx
+}
+
+// Await waits for either the given context to be cancelled - in which case the function returns false - or for all of the supplied awaitables to be done - in which case the function returns true.
+func Await(ctx context.Context, as ...Awaitable) bool {
+	if len(as) == 0 {
+		return ctx.Err() == nil
+	}
+	done := waitFor(as[0])
+	select {
+	case <-ctx.Done():
+		done()
+		return false
+	case <-as[0].Done():
+		done()
+		return Await(ctx, as[1:]...)
+	}
+}
+
+// AwaitAny waits for either the given context to be cancelled - in which case the function returns false - or for any of the supplied awaitables to be done - in which case the function returns its index and true. If some of the awaitables are already done when the function is called, the one with the lowest index is reported.
+func AwaitAny(ctx context.Context, as ...Awaitable) (int, bool) {
+	cases := make([]reflect.SelectCase, 0, len(as)+1)
+	for i, a := range as {
+		d := a.Done()
+		select {
+		case <-d:
+			return i, true
+		default:
+		}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(d)})
+	}
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	i, _, _ := reflect.Select(cases)
+	if i == len(as) {
+		return -1, false
+	}
+	return i, true
+}
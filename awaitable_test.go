@@ -0,0 +1,120 @@
+package future_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+func IsAwaitableDone(a future.Awaitable) bool {
+	select {
+	case <-a.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFromSignal(t *testing.T) {
+	t.Parallel()
+
+	signal := make(chan struct{})
+	s := future.FromSignal(signal)
+	if IsAwaitableDone(s) {
+		t.Errorf("signal channel awaitable done before closing the channel")
+	}
+	close(signal)
+	<-s.Done()
+}
+
+func TestFromWaitGroup(t *testing.T) {
+	t.Parallel()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	a := future.FromWaitGroup(wg)
+	if IsAwaitableDone(a) {
+		t.Errorf("wait group awaitable done before wait group counter dropped to zero")
+	}
+	wg.Done()
+	<-a.Done()
+}
+
+func TestFromWait(t *testing.T) {
+	t.Parallel()
+
+	release := futuretest.NewStartCond()
+	a := future.FromWait(release.Wait)
+	if IsAwaitableDone(a) {
+		t.Errorf("wait awaitable done before wait function returned")
+	}
+	release.Start()
+	<-a.Done()
+}
+
+func TestAwaitMixed(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	ctx := t.Context()
+	child, cancelChild := context.WithCancel(ctx)
+	ch := make(chan string, 1)
+
+	got := future.Go(func() bool {
+		return future.Await(ctx, f, future.FromWaitGroup(wg), future.FromContext(child), future.FromChan(ch))
+	})
+
+	f.Resolve(1)
+	wg.Done()
+	cancelChild()
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
+		t.Errorf("await returned %v before all awaitables were done", got.Get())
+	}
+	ch <- "done"
+	if !got.Get() {
+		t.Errorf("await returned false after all awaitables were done")
+	}
+}
+
+func TestAwaitAny(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := &future.Future[int]{}, &future.Future[int]{}, &future.Future[int]{}
+
+	type indexResult struct {
+		index int
+		ok    bool
+	}
+	got := future.Go(func() indexResult {
+		i, ok := future.AwaitAny(t.Context(), a, b, c)
+		return indexResult{i, ok}
+	})
+	b.Resolve(1)
+	if r := got.Get(); r.index != 1 || !r.ok {
+		t.Errorf("await any returned (%d, %v), expected (1, true)", r.index, r.ok)
+	}
+
+	c.Resolve(1)
+	if i, ok := future.AwaitAny(t.Context(), a, c, b); i != 1 || !ok {
+		t.Errorf("await any returned (%d, %v) for already done awaitables, expected (1, true)", i, ok)
+	}
+}
+
+func TestAwaitAnyCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	got := future.Go(func() bool {
+		_, ok := future.AwaitAny(ctx, &future.Future[int]{}, &future.Future[string]{})
+		return ok
+	})
+	cancel()
+	if got.Get() {
+		t.Errorf("await any returned true after context cancel")
+	}
+}
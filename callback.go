@@ -0,0 +1,117 @@
+package future
+
+import (
+	"sync/atomic"
+)
+
+// callback is a node of a lock-free stack of functions to call once the future is resolved.
+type callback struct {
+	next  *callback
+	state atomic.Uint32
+	fn    func()
+}
+
+const (
+	callbackPending uint32 = iota
+	callbackStarted
+	callbackStopped
+)
+
+// settledCallbacks is a sentinel stored as the callbacks stack head of resolved futures.
+var settledCallbacks = &callback{} //nolint:gochecknoglobals // sentinel value
+
+func (c *callback) run() {
+	if c.state.CompareAndSwap(callbackPending, callbackStarted) {
+		c.fn()
+	}
+}
+
+func (c *callback) stop() bool {
+	return c.state.CompareAndSwap(callbackPending, callbackStopped)
+}
+
+// OnResolve arranges for fn to be called with the value of the future once it is resolved with a value. If the future is rejected, fn is never called. Functions are called in the order of registration, by the goroutine that resolves the future, or immediately by OnResolve if the future is already resolved. As they block the resolver, they should be short - use AfterFunc to run longer functions using an executor.
+//
+// Calling the returned stop function prevents fn from being called. It returns true if it did so, or false if fn was already started (or if the future was rejected). Memory of stopped functions is reclaimed once the future is resolved.
+//
+// Unlike waiting on Done, registering a callback does not require a goroutine per future, making it suitable for tracking large numbers of pending futures.
+func (f *Future[T]) OnResolve(fn func(T)) (stop func() bool) {
+	return f.afterResolve(func() {
+		if v, err := f.Result(); err == nil {
+			fn(v)
+		}
+	}).stop
+}
+
+// OnReject arranges for fn to be called with the error of the future once it is rejected. If the future is resolved with a value, fn is never called. Otherwise it behaves just like OnResolve.
+func (f *Future[T]) OnReject(fn func(error)) (stop func() bool) {
+	return f.afterResolve(func() {
+		if err := f.Err(); err != nil {
+			fn(err)
+		}
+	}).stop
+}
+
+// AfterFunc arranges for fn to be run, using the configured executor (by default in its own goroutine), once f is resolved either with a value or an error. It is the future counterpart of context.AfterFunc.
+//
+// Calling the returned stop function prevents fn from being submitted to the executor. It returns true if it did so, or false if fn was already submitted.
+func AfterFunc[T any](f *Future[T], fn func(T, error), opts ...Option) (stop func() bool) {
+	o := newOptions(opts)
+	return f.afterResolve(func() {
+		o.executor.Go(func() {
+			fn(f.Result())
+		})
+	}).stop
+}
+
+// afterResolve arranges for fn to be called once the future is resolved. Functions are called in the order of registration, by the goroutine that resolves the future, or immediately if the future is already resolved and all previously registered functions were called.
+func (f *Future[T]) afterResolve(fn func()) *callback {
+	c := &callback{fn: fn}
+	for {
+		head := f.cp.Load()
+		if head == settledCallbacks {
+			c.run()
+			return c
+		}
+		c.next = head
+		if f.cp.CompareAndSwap(head, c) {
+			return c
+		}
+	}
+}
+
+// runCallbacks calls registered functions in batches, until it manages to mark the stack as drained. Functions registered while a batch is being run are left for the next batch, so that registration order is preserved even when they race with the resolvement.
+//
+// If a function panics, the remaining ones are still called, and the stack is still marked as drained, before the panic propagates - otherwise functions registered later would never be called.
+func (f *Future[T]) runCallbacks(batch *callback) {
+	drained := false
+	defer func() {
+		if !drained {
+			f.runCallbacks(batch)
+		}
+	}()
+	for {
+		for batch != nil {
+			c := batch
+			batch = c.next
+			c.run()
+		}
+		head := f.cp.Load()
+		if head == nil {
+			if f.cp.CompareAndSwap(nil, settledCallbacks) {
+				drained = true
+				return
+			}
+			continue
+		}
+		if !f.cp.CompareAndSwap(head, nil) {
+			continue
+		}
+		for head != nil {
+			next := head.next
+			head.next = batch
+			batch = head
+			head = next
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package future_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+func TestOnResolve(t *testing.T) {
+	t.Parallel()
+
+	log := &Log{}
+	f := &future.Future[int]{}
+	f.OnResolve(func(v int) { log.Add("a") })
+	stopB := f.OnResolve(func(v int) { log.Add("b") })
+	f.OnResolve(func(v int) {
+		log.Add("c")
+		if v != 1 {
+			t.Errorf("callback called with %d, expected 1", v)
+		}
+	})
+	f.OnReject(func(err error) { log.Add("never") })
+
+	if !stopB() {
+		t.Errorf("stop of pending callback returned false")
+	}
+	log.Must(t)
+
+	f.Resolve(1)
+	log.Must(t, "a", "c")
+
+	stopD := f.OnResolve(func(v int) { log.Add("d") })
+	log.Must(t, "a", "c", "d")
+	if stopB() || stopD() {
+		t.Errorf("stop of already stopped or called callback returned true")
+	}
+}
+
+func TestOnResolvePanic(t *testing.T) {
+	t.Parallel()
+
+	log := &Log{}
+	f := &future.Future[int]{}
+	f.OnResolve(func(int) { log.Add("a") })
+	f.OnResolve(func(int) { panic("callback") })
+	f.OnResolve(func(int) { log.Add("c") })
+	func() {
+		defer func() {
+			if r := recover(); r != "callback" {
+				t.Errorf("resolve recovered %v, expected panic of the callback", r)
+			}
+		}()
+		f.Resolve(1)
+	}()
+	log.Must(t, "a", "c")
+
+	f.OnResolve(func(int) { log.Add("d") })
+	log.Must(t, "a", "c", "d")
+}
+
+func TestOnReject(t *testing.T) {
+	t.Parallel()
+
+	log := &Log{}
+	f := &future.Future[int]{}
+	f.OnResolve(func(v int) { log.Add("never") })
+	f.OnReject(func(err error) {
+		log.Add("a")
+		if !errors.Is(err, errTest) {
+			t.Errorf("callback called with %v, expected %v", err, errTest)
+		}
+	})
+	f.Reject(errTest)
+	f.OnReject(func(err error) { log.Add("b") })
+	log.Must(t, "a", "b")
+}
+
+func TestAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	got := &future.Future[int]{}
+	future.AfterFunc(f, func(v int, err error) {
+		got.Resolve(v)
+	})
+	stopped := future.AfterFunc(f, func(v int, err error) {
+		t.Errorf("stopped function called")
+	}, future.WithExecutor(future.Inline{}))
+
+	if !stopped() {
+		t.Errorf("stop of pending function returned false")
+	}
+	f.Resolve(1)
+	if v := got.Get(); v != 1 {
+		t.Errorf("function called with %d, expected 1", v)
+	}
+}
+
+func TestOnResolveConcurrent(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	start := futuretest.NewStartCond()
+	calls := atomic.Int64{}
+	wg := &sync.WaitGroup{}
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start.Wait()
+			for range 20 {
+				f.OnResolve(func(int) { calls.Add(1) })
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start.Wait()
+		f.Resolve(1)
+	}()
+	start.Start()
+	wg.Wait()
+
+	if c := calls.Load(); c != 50*20 {
+		t.Errorf("callbacks called %d times, expected %d", c, 50*20)
+	}
+}
@@ -0,0 +1,56 @@
+package future
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrChanClosed is the error used to reject futures created by FromChan when the channel gets closed without delivering a value.
+var ErrChanClosed = errors.New("future: channel closed without a value")
+
+// FromChan returns a future that is resolved with the first value received from the given channel, or rejected with ErrChanClosed if the channel is closed without delivering a value. The value is received by a task run using the configured executor (by default in a new goroutine), so it is consumed and lost to other receivers of the channel. The task occupies a worker of the executor until a value is received or the channel is closed, so with bounded executors it counts against their concurrency. If the task is dropped instead of being run, the future is rejected with the cause. To await a signal channel, which is closed rather than sent to, use FromSignal instead - it starts no goroutine.
+func FromChan[T any](ch <-chan T, opts ...Option) *Future[T] {
+	o := newOptions(opts)
+	f := newFuture[T](context.Background(), o, nil)
+	labels := o.labels()
+	o.submit(func() {
+		f.m.run(labels, func() {
+			v, ok := <-ch
+			if !ok {
+				f.TryReject(ErrChanClosed)
+				return
+			}
+			f.TryResolve(v)
+		})
+	}, f.cancel)
+	return f
+}
+
+// Chan returns a new channel that receives the value of the future once it is resolved, after which the channel is closed. If the future is rejected, the channel is closed without delivering a value. Every call returns a separate channel, with capacity of 1, so that nothing blocks (and no goroutine leaks) if nobody ever receives from it.
+func (f *Future[T]) Chan() <-chan T {
+	ch := make(chan T, 1)
+	f.afterResolve(func() {
+		if v, err := f.Result(); err == nil {
+			ch <- v
+		}
+		close(ch)
+	})
+	return ch
+}
+
+// Pipe sends the value of the future to the given channel once the future is resolved with a value. Nothing is sent if the future is rejected. The send is performed using the configured executor (by default in a new goroutine) and is abandoned when the given context is cancelled, so it does not block forever if nobody receives from the channel.
+func Pipe[T any](ctx context.Context, f *Future[T], ch chan<- T, opts ...Option) {
+	o := newOptions(opts)
+	f.afterResolve(func() {
+		v, err := f.Result()
+		if err != nil {
+			return
+		}
+		o.executor.Go(func() {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+			}
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+// CountGoroutines returns the number of goroutines with the given function in their stacks.
+func CountGoroutines(fn string) int {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	count := 0
+	for g := range strings.SplitSeq(string(buf), "\n\n") {
+		if strings.Contains(g, fn) {
+			count++
+		}
+	}
+	return count
+}
+
+func NoLeakedGoroutines(t *testing.T, fn string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for CountGoroutines(fn) > 0 {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines with %s in stack are still running", CountGoroutines(fn), fn)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFromChan(t *testing.T) {
+	t.Parallel()
+
+	values := make(chan int)
+	closed := make(chan int)
+	a, c := future.FromChan(values), future.FromChan(closed)
+
+	if futuretest.IsSuccessful(futuretest.GetResult(a, futuretest.IsDone)) {
+		t.Errorf("future resolved before receiving value")
+	}
+	values <- 1
+	if v, err := a.Result(); v != 1 || err != nil {
+		t.Errorf("future resolved with (%v, %v), expected (1, nil)", v, err)
+	}
+
+	close(closed)
+	if err := c.Err(); !errors.Is(err, future.ErrChanClosed) {
+		t.Errorf("future rejected with %v, expected %v", err, future.ErrChanClosed)
+	}
+}
+
+func TestChan(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	a, b := f.Chan(), f.Chan()
+	f.Resolve(1)
+	c := f.Chan()
+	for i, ch := range []<-chan int{a, b, c} {
+		if v, ok := <-ch; v != 1 || !ok {
+			t.Errorf("channel %d received (%v, %v), expected (1, true)", i, v, ok)
+		}
+		if _, ok := <-ch; ok {
+			t.Errorf("channel %d not closed after delivering value", i)
+		}
+	}
+
+	r := future.Rejected[int](errTest)
+	if _, ok := <-r.Chan(); ok {
+		t.Errorf("channel of rejected future delivered value")
+	}
+}
+
+func TestPipe(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	ch := make(chan int)
+	future.Pipe(t.Context(), f, ch)
+	future.Pipe(t.Context(), future.Rejected[int](errTest), ch)
+	f.Resolve(1)
+	if v := <-ch; v != 1 {
+		t.Errorf("pipe delivered %d, expected 1", v)
+	}
+	select {
+	case v := <-ch:
+		t.Errorf("pipe of rejected future delivered %d", v)
+	default:
+	}
+}
+
+func TestChanNoLeaks(t *testing.T) { //nolint:paralleltest // counting goroutines is unreliable in parallel tests
+	for range 10 {
+		// resolved futures with channels that nobody receives from
+		f := &future.Future[int]{}
+		f.Chan()
+		f.Resolve(1)
+		f.Chan()
+
+		// channels that deliver a value or get closed
+		values, closed := make(chan int, 1), make(chan int)
+		future.FromChan(values)
+		future.FromChan(closed)
+		values <- 1
+		close(closed)
+
+		// pipes that nobody receives from
+		ctx, cancel := context.WithCancel(t.Context())
+		future.Pipe(ctx, future.Resolved(1), make(chan int))
+		cancel()
+	}
+	NoLeakedGoroutines(t, "go-future.FromChan")
+	NoLeakedGoroutines(t, "go-future.Pipe")
+	NoLeakedGoroutines(t, "go-future.(*Future[...]).Chan")
+}
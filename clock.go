@@ -0,0 +1,43 @@
+package future
+
+import (
+	"time"
+)
+
+// Clock is a source of time for the time based helpers of this package. Replacing it (see WithClock) allows tests to control the passage of time.
+//
+// Clock implementations must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls fn (in its own goroutine or in the goroutine that advances the clock) once the duration elapses.
+	AfterFunc(d time.Duration, fn func()) Timer
+}
+
+// Timer is a single event scheduled with Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing. It returns false if the timer has already fired or been stopped.
+	Stop() bool
+}
+
+// SystemClock is a clock that uses the system time. It is the default clock used by helpers in this package.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc waits for the duration to elapse and then calls fn in its own goroutine.
+func (SystemClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return time.AfterFunc(d, fn)
+}
+
+// WithClock sets the clock used to measure time.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package future
+
+import (
+	"context"
+	"runtime"
+	"runtime/trace"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const maxStackDepth = 32
+
+// meta holds debugging information of a future. It is attached only to futures created (by New or by helpers of this package) while some debugging facility or hooks are enabled, and it never references the future itself, so that it can outlive it.
+type meta struct {
+	id      uint64
+	parent  uint64 // future the future is resolved from, by Then, Catch, Fallback (the first of its futures) or the Join functions, zero if none
+	name    string
+	created time.Time // time the future was created at, by the clock of leak detection if enabled
+	pcs     []uintptr // program counters of the stack that created the future
+
+	state    atomic.Int32 // FutureState
+	result   any          // result[T] of the future, stored before its done channel is closed, so that waiters can read it without referencing the future
+	observed atomic.Bool  // whether the done channel was ever requested, by Done or by a waiter
+	waiters  atomic.Int64 // number of goroutines blocked in Wait (and in Get, Err and Result)
+
+	leaks      *LeakDetection // leak detection configuration the future is watched by, nil if none
+	leakTimer  Timer
+	registry   *registry // registry the future is listed in, nil if none
+	registered time.Time // time the future was created at, by the clock of the registry
+
+	producer  atomic.Uint64 // goroutine expected to resolve the future, zero if unknown (tracked only by the registry)
+	mu        sync.Mutex
+	waiting   []uint64 // goroutines blocked waiting for the future (tracked only by the registry)
+	dependsOn []uint64 // futures the future is resolved from
+
+	traceCtx context.Context //nolint:containedctx // context of the runtime/trace task of the future, nil unless traced (see WithTracing)
+	task     *trace.Task
+
+	observers []Observer // observers returned by hooks (see RegisterHooks)
+}
+
+// result is the result of a future, as stored in its debugging information.
+type result[T any] struct {
+	v   T
+	err error
+}
+
+var lastID atomic.Uint64 //nolint:gochecknoglobals // source of future identifiers
+
+// New creates a new pending future. Unlike zero value futures, futures created by New (or by helpers of this package) are tracked by debugging facilities, like leak detection (see EnableLeakDetection) and the registry (see EnableRegistry), when they are enabled, and observed by hooks (see RegisterHooks). Out of the given options, only WithName, WithRepanic, WithTracing, WithHookContext and WithExecutor (for hooks of the executor, see Hooked) are used.
+func New[T any](opts ...Option) *Future[T] {
+	return newFuture[T](context.Background(), newOptions(opts), nil)
+}
+
+// WithName sets the name of created futures, identifying them in reports of debugging facilities.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// newFuture creates a new pending future, attaching debugging information to it if any debugging facility or hooks are enabled. The context is the one given to the helper creating the future, passed to hooks.
+func newFuture[T any](ctx context.Context, o *options, parent *meta) *Future[T] { //nolint:contextcheck // contexts passed with WithHookContext and WithTracing take precedence
+	f := &Future[T]{rp: o.repanic}
+	ld, reg, hooks := leakDetection.Load(), currentRegistry.Load(), globalHooks.Load()
+	if ld == nil && reg == nil && o.traceCtx == nil && hooks == nil && o.hooks == nil {
+		return f
+	}
+	m := &meta{
+		id:       lastID.Add(1),
+		name:     o.name,
+		pcs:      callers(3), //nolint:mnd // skips runtime.Callers, callers and newFuture
+		leaks:    ld,
+		registry: reg,
+	}
+	if parent != nil {
+		m.parent = parent.id
+		m.dependOn(parent)
+	}
+	switch {
+	case ld != nil:
+		m.created = ld.clock().Now()
+	case reg != nil:
+		m.created = reg.clock.Now()
+	default:
+		m.created = time.Now()
+	}
+	if ld != nil {
+		m.watch()
+	}
+	if reg != nil {
+		m.registered = reg.clock.Now()
+		reg.add(m)
+	}
+	if o.traceCtx != nil {
+		m.startTask(o.traceCtx)
+	}
+	if hooks != nil || o.hooks != nil {
+		switch {
+		case o.hookCtx != nil:
+			ctx = o.hookCtx
+		case o.traceCtx != nil:
+			ctx = o.traceCtx
+		}
+		if hooks != nil {
+			m.observe(ctx, *hooks)
+		}
+		m.observe(ctx, o.hooks)
+	}
+	runtime.AddCleanup(f, (*meta).collected, m)
+	f.m = m
+	return f
+}
+
+func (m *meta) settle(err error, cancelled bool) {
+	if err != nil {
+		m.state.Store(int32(FutureRejected))
+	} else {
+		m.state.Store(int32(FutureResolved))
+	}
+	if m.leakTimer != nil {
+		m.leakTimer.Stop()
+	}
+	if m.task != nil {
+		m.endTask(err)
+	}
+	for _, obs := range m.observers {
+		if cancelled {
+			obs.Cancelled(err)
+		} else {
+			obs.Resolved(err)
+		}
+	}
+}
+
+func (m *meta) pending() bool {
+	return FutureState(m.state.Load()) == FuturePending
+}
+
+// collected is run once the future is garbage collected.
+func (m *meta) collected() {
+	if m.registry != nil {
+		m.registry.remove(m)
+	}
+	if !m.pending() {
+		return
+	}
+	if m.leaks != nil && (m.observed.Load() || m.waiters.Load() > 0) {
+		m.reportLeak(LeakCollected)
+	}
+	for _, obs := range m.observers {
+		obs.Collected()
+	}
+}
+
+// wait records the calling goroutine as waiting for the future, until the returned function is called.
+func (m *meta) wait() (done func()) {
+	m.waiters.Add(1)
+	var id uint64
+	if m.registry != nil {
+		id = goid()
+		m.mu.Lock()
+		m.waiting = append(m.waiting, id)
+		m.mu.Unlock()
+	}
+	var region *trace.Region
+	if m.traceCtx != nil {
+		region = trace.StartRegion(m.traceCtx, "future.Wait")
+	}
+	var ended []func()
+	for _, obs := range m.observers {
+		if end := obs.WaitStarted(); end != nil {
+			ended = append(ended, end)
+		}
+	}
+	return func() {
+		for _, end := range ended {
+			end()
+		}
+		if region != nil {
+			region.End()
+		}
+		if m.registry != nil {
+			m.mu.Lock()
+			if i := slices.Index(m.waiting, id); i >= 0 {
+				m.waiting = slices.Delete(m.waiting, i, i+1)
+			}
+			m.mu.Unlock()
+		}
+		m.waiters.Add(-1)
+	}
+}
+
+// stack returns the stack that created the future, formatted like stacks of goroutines in panics.
+func (m *meta) stack() string {
+	b := &strings.Builder{}
+	frames := runtime.CallersFrames(m.pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			b.WriteString(frame.Function + "(...)\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + "\n")
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	return pcs[:runtime.Callers(skip, pcs)]
+}
@@ -0,0 +1,100 @@
+package future
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventLoop is an executor that runs all tasks serially, on a single goroutine that drives the loop (see RunUntil), in a well defined order. It mirrors the event loop of JavaScript engines: tasks submitted with Go are microtasks and tasks submitted with Post are macrotasks. Every macrotask is followed by running all queued microtasks (including the ones queued in the meantime), before the next macrotask is started.
+//
+// Continuations attached to futures with the event loop as executor (see Then and Catch) are queued as microtasks once the future is resolved, in the order they were attached - just like promise reactions in JavaScript. As a consequence, a continuation is never called synchronously by the function that attaches it, even if the future is already resolved.
+type EventLoop struct {
+	running atomic.Bool
+	wake    chan struct{} // signals new tasks to the blocked loop
+
+	mu    sync.Mutex
+	micro []func()
+	macro []func()
+}
+
+// NewEventLoop creates a new, empty event loop.
+func NewEventLoop() *EventLoop {
+	return &EventLoop{wake: make(chan struct{}, 1)}
+}
+
+// Go queues task as a microtask.
+func (l *EventLoop) Go(task func()) {
+	l.mu.Lock()
+	l.micro = append(l.micro, task)
+	l.mu.Unlock()
+	l.signal()
+}
+
+// Post queues task as a macrotask.
+func (l *EventLoop) Post(task func()) {
+	l.mu.Lock()
+	l.macro = append(l.macro, task)
+	l.mu.Unlock()
+	l.signal()
+}
+
+// RunUntil runs queued tasks on the calling goroutine until the given future (or any other awaitable) is resolved. The future is checked each time the microtask queue is drained, so all microtasks queued as an effect of its resolvement are run before RunUntil returns. Tasks that remain queued are run by the next call to RunUntil.
+//
+// RunUntil panics if the loop is already being run.
+func (l *EventLoop) RunUntil(f Awaitable) {
+	if !l.running.CompareAndSwap(false, true) {
+		panic("future: event loop is already running")
+	}
+	defer l.running.Store(false)
+
+	done := f.Done()
+	for {
+		for task := l.next(&l.micro); task != nil; task = l.next(&l.micro) {
+			task()
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if task := l.next(&l.macro); task != nil {
+			task()
+			continue
+		}
+		select {
+		case <-done:
+		case <-l.wake:
+		}
+	}
+}
+
+// Stats returns a snapshot of the load of the loop - a single worker, busy while the loop is being run (see RunUntil), and the number of queued microtasks and macrotasks.
+func (l *EventLoop) Stats() ExecutorStats {
+	busy := 0
+	if l.running.Load() {
+		busy = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ExecutorStats{Workers: 1, Busy: busy, Queued: len(l.micro) + len(l.macro)}
+}
+
+func (l *EventLoop) signal() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *EventLoop) next(queue *[]func()) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(*queue) == 0 {
+		*queue = nil
+		return nil
+	}
+	task := (*queue)[0]
+	(*queue)[0] = nil
+	*queue = (*queue)[1:]
+	return task
+}
@@ -0,0 +1,263 @@
+package future_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+// The tests below are adapted from the Promises/A+ conformance test suite (https://github.com/promises-aplus/promises-tests), sections 2.1 and 2.2. Futures play the role of promises, Then and Catch the role of onFulfilled and onRejected handlers, and an event loop the role of the JavaScript platform.
+
+type Log struct {
+	entries []string
+}
+
+func (l *Log) Add(entry string) {
+	l.entries = append(l.entries, entry)
+}
+
+func (l *Log) Must(t *testing.T, expected ...string) {
+	t.Helper()
+	if !slices.Equal(l.entries, expected) {
+		t.Errorf("got log %q, expected %q", l.entries, expected)
+	}
+}
+
+func Logging[T any](l *Log, entry string) func(T) (T, error) {
+	return func(v T) (T, error) {
+		l.Add(entry)
+		return v, nil
+	}
+}
+
+func LoggingCatch[T any](l *Log, entry string) func(error) (T, error) {
+	return func(err error) (T, error) {
+		var z T
+		l.Add(entry)
+		return z, err
+	}
+}
+
+// 2.1.2 / 2.1.3: when fulfilled or rejected, a promise must not transition to any other state.
+func TestAplusStateIsFinal(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	f := &future.Future[int]{}
+	loop.Post(func() {
+		f.Resolve(1)
+		if f.TryReject(errTest) || f.TryResolve(2) {
+			log.Add("transitioned")
+		}
+	})
+	loop.RunUntil(f)
+	log.Must(t)
+	if v, err := f.Result(); v != 1 || err != nil {
+		t.Errorf("future resolved with (%v, %v), expected (1, nil)", v, err)
+	}
+}
+
+// 2.2.2: onFulfilled must be called after the promise is fulfilled, with its value, and not more than once.
+func TestAplusOnFulfilled(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	f := &future.Future[int]{}
+	got := future.Then(f, func(v int) (int, error) {
+		log.Add("fulfilled")
+		return v, nil
+	}, future.WithExecutor(loop))
+	loop.Post(func() {
+		log.Add("resolving")
+		f.Resolve(1)
+		f.TryResolve(2)
+	})
+	loop.RunUntil(got)
+	log.Must(t, "resolving", "fulfilled")
+	if v := got.Get(); v != 1 {
+		t.Errorf("handler received %d, expected 1", v)
+	}
+}
+
+// 2.2.3: onRejected must be called after the promise is rejected, with its reason, and not more than once.
+func TestAplusOnRejected(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	f := &future.Future[int]{}
+	got := future.Catch(f, func(err error) (int, error) {
+		log.Add("rejected")
+		if !errors.Is(err, errTest) {
+			t.Errorf("handler received %v, expected %v", err, errTest)
+		}
+		return 2, nil
+	}, future.WithExecutor(loop))
+	loop.Post(func() {
+		log.Add("rejecting")
+		f.Reject(errTest)
+		f.TryReject(errTest)
+	})
+	loop.RunUntil(got)
+	log.Must(t, "rejecting", "rejected")
+	if v := got.Get(); v != 2 {
+		t.Errorf("catch resolved with %d, expected 2", v)
+	}
+}
+
+// 2.2.4: onFulfilled or onRejected must not be called until the execution context stack contains only platform code.
+func TestAplusAsynchronousHandlers(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	resolved, rejected := future.Resolved(1), future.Rejected[int](errTest)
+	attached := &future.Future[struct{}]{}
+	loop.Post(func() {
+		future.Then(resolved, Logging[int](log, "fulfilled"), future.WithExecutor(loop))
+		future.Catch(rejected, LoggingCatch[int](log, "rejected"), future.WithExecutor(loop))
+		log.Add("attached")
+		attached.Resolve(struct{}{})
+	})
+	loop.RunUntil(attached)
+	log.Must(t, "attached", "fulfilled", "rejected")
+}
+
+// 2.2.6: then may be called multiple times on the same promise and handlers must execute in the order of their originating calls to then.
+func TestAplusHandlersOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"pending", "resolved"} {
+		loop := future.NewEventLoop()
+		log := &Log{}
+		f := &future.Future[int]{}
+		if name == "resolved" {
+			f.Resolve(1)
+		}
+		a := future.Then(f, Logging[int](log, "a"), future.WithExecutor(loop))
+		b := future.Catch(f, LoggingCatch[int](log, "never"), future.WithExecutor(loop))
+		c := future.Then(f, func(v int) (int, error) {
+			log.Add("c")
+			future.Then(f, Logging[int](log, "e"), future.WithExecutor(loop))
+			return v, nil
+		}, future.WithExecutor(loop))
+		d := future.Then(f, Logging[int](log, "d"), future.WithExecutor(loop))
+		if name == "pending" {
+			loop.Post(func() { f.Resolve(1) })
+		}
+		loop.RunUntil(d)
+		for _, x := range []*future.Future[int]{a, b, c} {
+			if !futuretest.IsSuccessful(futuretest.GetResult(x, futuretest.IsDone)) {
+				t.Errorf("%s: handler future not resolved after running the loop", name)
+			}
+		}
+		log.Must(t, "a", "c", "d", "e")
+	}
+}
+
+// 2.2.7: then must return a promise, rejected if the handler fails and adopting the state of the original promise if the matching handler is missing.
+func TestAplusThenReturnsFuture(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	f := &future.Future[int]{}
+	failed := future.Then(f, func(int) (string, error) { return "", errTest }, future.WithExecutor(loop))
+	passed := future.Catch(failed, LoggingCatch[string](log, "caught"), future.WithExecutor(loop))
+	skipped := future.Then(passed, func(string) (string, error) {
+		log.Add("never")
+		return "", nil
+	}, future.WithExecutor(loop))
+	value := future.Catch(f, LoggingCatch[int](log, "never"), future.WithExecutor(loop))
+	loop.Post(func() { f.Resolve(1) })
+	loop.RunUntil(skipped)
+	loop.RunUntil(value)
+	log.Must(t, "caught")
+	if err := skipped.Err(); !errors.Is(err, errTest) {
+		t.Errorf("chain rejected with %v, expected %v", err, errTest)
+	}
+	if v, err := value.Result(); v != 1 || err != nil {
+		t.Errorf("catch on fulfilled future resolved with (%v, %v), expected (1, nil)", v, err)
+	}
+}
+
+// 2.2.7.3 / 2.2.7.4: a promise adopting the state of another one, without calling a handler, does so in a microtask of its own.
+func TestAplusPassThroughOrder(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	rejected := &future.Future[int]{}
+	passedRejection := future.Then(rejected, Logging[int](log, "never"), future.WithExecutor(loop))
+	_ = future.Catch(passedRejection, LoggingCatch[int](log, "catch passed"), future.WithExecutor(loop))
+	_ = future.Catch(rejected, LoggingCatch[int](log, "catch"), future.WithExecutor(loop))
+	resolved := &future.Future[int]{}
+	passedValue := future.Catch(resolved, LoggingCatch[int](log, "never"), future.WithExecutor(loop))
+	thenPassed := future.Then(passedValue, Logging[int](log, "then passed"), future.WithExecutor(loop))
+	_ = future.Then(resolved, Logging[int](log, "then"), future.WithExecutor(loop))
+	loop.Post(func() {
+		rejected.Reject(errTest)
+		resolved.Resolve(1)
+	})
+	loop.RunUntil(thenPassed)
+	log.Must(t, "catch", "then", "catch passed", "then passed")
+}
+
+func TestEventLoopMicrotasksFirst(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	log := &Log{}
+	done := &future.Future[struct{}]{}
+	loop.Post(func() {
+		log.Add("macro 1")
+		loop.Post(func() {
+			log.Add("macro 3")
+			done.Resolve(struct{}{})
+		})
+		loop.Go(func() {
+			log.Add("micro 1")
+			loop.Go(func() { log.Add("micro 2") })
+		})
+	})
+	loop.Post(func() { log.Add("macro 2") })
+	loop.RunUntil(done)
+	log.Must(t, "macro 1", "micro 1", "micro 2", "macro 2", "macro 3")
+}
+
+func TestEventLoopExternalResolve(t *testing.T) {
+	t.Parallel()
+
+	loop := future.NewEventLoop()
+	f := &future.Future[int]{}
+	got := future.Then(f, func(v int) (int, error) { return v + 1, nil }, future.WithExecutor(loop))
+	go f.Resolve(1)
+	loop.RunUntil(got)
+	if v := got.Get(); v != 2 {
+		t.Errorf("continuation resolved with %d, expected 2", v)
+	}
+}
+
+func TestEventLoopStats(t *testing.T) {
+	t.Parallel()
+
+	l := future.NewEventLoop()
+	l.Go(func() {})
+	l.Post(func() {})
+	if s := l.Stats(); s != (future.ExecutorStats{Workers: 1, Queued: 2}) {
+		t.Errorf("event loop stats are %+v, expected 2 queued tasks", s)
+	}
+	f := &future.Future[int]{}
+	l.Post(func() {
+		if s := l.Stats(); s != (future.ExecutorStats{Workers: 1, Busy: 1}) {
+			t.Errorf("running event loop stats are %+v, expected a busy worker", s)
+		}
+		f.Resolve(1)
+	})
+	l.RunUntil(f)
+}
@@ -54,16 +54,21 @@ func main() {
 	onion := GetOnion(ctx, eg)
 	garlic := GetGarlic(ctx, eg)
 	grater := GetGrater(ctx, eg)
-	cookedSpaghetti := CookSpaghetti(ctx, eg, boilingWater, rawSpaghetti)
+	cookedSpaghetti := CookSpaghetti(ctx, boilingWater, rawSpaghetti)
 	choppedTomatoes, choppedOnion := ChopVegetables(ctx, eg, slicingBoard, tomatoes, onion)
-	grateGarlic := GrateGarlic(ctx, eg, grater, garlic)
-	cookedVegetables := CookVegetables(ctx, eg, boilingWater, choppedTomatoes, choppedOnion, grateGarlic)
-	dish := PutOnPlate(ctx, eg, cookedSpaghetti, cookedVegetables)
-
-	if err := eg.Wait(); err != nil {
+	grateGarlic := GrateGarlic(ctx, grater, garlic)
+	cookedVegetables := CookVegetables(ctx, boilingWater, choppedTomatoes, choppedOnion, grateGarlic)
+	dish := PutOnPlate(ctx, cookedSpaghetti, cookedVegetables)
+
+	// The group context is cancelled once the group is done, so the dish must be awaited first.
+	d, err := dish.Result()
+	if err == nil {
+		err = eg.Wait()
+	}
+	if err != nil {
 		fmt.Printf("error: %s\n", err.Error())
 	} else {
-		fmt.Printf("result:\n%s\n", Format(dish.Get()))
+		fmt.Printf("result:\n%s\n", Format(d))
 	}
 }
 
@@ -166,17 +171,11 @@ func GetGrater(ctx context.Context, eg *errgroup.Group) *future.Future[Grater] {
 
 type CookedSpaghetti string
 
-func CookSpaghetti(ctx context.Context, eg *errgroup.Group, bw *future.Future[BoilingWater], rs *future.Future[RawSpaghetti]) *future.Future[CookedSpaghetti] {
-	cs := &future.Future[CookedSpaghetti]{}
-	eg.Go(func() error {
-		if !future.Await(ctx, bw.Done(), rs.Done()) {
-			return nil
-		}
+func CookSpaghetti(ctx context.Context, bw *future.Future[BoilingWater], rs *future.Future[RawSpaghetti]) *future.Future[CookedSpaghetti] {
+	return future.Join2(ctx, bw, rs, func(bw BoilingWater, rs RawSpaghetti) (CookedSpaghetti, error) {
 		Do("cooking spaghetti")
-		cs.Resolve(CookedSpaghetti("CookedSpaghetti:\n" + Format(bw.Get(), rs.Get())))
-		return nil
+		return CookedSpaghetti("CookedSpaghetti:\n" + Format(bw, rs)), nil
 	})
-	return cs
 }
 
 type ChoppedTomatoes string
@@ -185,7 +184,7 @@ type ChoppedOnion string
 func ChopVegetables(ctx context.Context, eg *errgroup.Group, sb *future.Future[SlicingBoard], t *future.Future[Tomatoes], o *future.Future[Onion]) (*future.Future[ChoppedTomatoes], *future.Future[ChoppedOnion]) {
 	ct, co := &future.Future[ChoppedTomatoes]{}, &future.Future[ChoppedOnion]{}
 	eg.Go(func() error {
-		if !future.Await(ctx, sb.Done(), t.Done(), o.Done()) {
+		if !future.Await(ctx, sb, t, o) {
 			return nil
 		}
 		Do("chopping tomatoes and onion")
@@ -198,45 +197,27 @@ func ChopVegetables(ctx context.Context, eg *errgroup.Group, sb *future.Future[S
 
 type GratedGarlic string
 
-func GrateGarlic(ctx context.Context, eg *errgroup.Group, gr *future.Future[Grater], ga *future.Future[Garlic]) *future.Future[GratedGarlic] {
-	gg := &future.Future[GratedGarlic]{}
-	eg.Go(func() error {
-		if !future.Await(ctx, gr.Done(), ga.Done()) {
-			return nil
-		}
+func GrateGarlic(ctx context.Context, gr *future.Future[Grater], ga *future.Future[Garlic]) *future.Future[GratedGarlic] {
+	return future.Join2(ctx, gr, ga, func(gr Grater, ga Garlic) (GratedGarlic, error) {
 		Do("grating garlic")
-		gg.Resolve(GratedGarlic("GratedGarlic:\n" + Format(gr.Get(), ga.Get())))
-		return nil
+		return GratedGarlic("GratedGarlic:\n" + Format(gr, ga)), nil
 	})
-	return gg
 }
 
 type CookedVegetables string
 
-func CookVegetables(ctx context.Context, eg *errgroup.Group, bw *future.Future[BoilingWater], ct *future.Future[ChoppedTomatoes], co *future.Future[ChoppedOnion], gg *future.Future[GratedGarlic]) *future.Future[CookedVegetables] {
-	cv := &future.Future[CookedVegetables]{}
-	eg.Go(func() error {
-		if !future.Await(ctx, bw.Done(), ct.Done(), co.Done(), gg.Done()) {
-			return nil
-		}
+func CookVegetables(ctx context.Context, bw *future.Future[BoilingWater], ct *future.Future[ChoppedTomatoes], co *future.Future[ChoppedOnion], gg *future.Future[GratedGarlic]) *future.Future[CookedVegetables] {
+	return future.Join4(ctx, bw, ct, co, gg, func(bw BoilingWater, ct ChoppedTomatoes, co ChoppedOnion, gg GratedGarlic) (CookedVegetables, error) {
 		Do("cooking vegetables")
-		cv.Resolve(CookedVegetables("CookedVegetables:\n" + Format(bw.Get(), ct.Get(), co.Get(), gg.Get())))
-		return nil
+		return CookedVegetables("CookedVegetables:\n" + Format(bw, ct, co, gg)), nil
 	})
-	return cv
 }
 
 type Dish string
 
-func PutOnPlate(ctx context.Context, eg *errgroup.Group, cs *future.Future[CookedSpaghetti], cv *future.Future[CookedVegetables]) *future.Future[Dish] {
-	d := &future.Future[Dish]{}
-	eg.Go(func() error {
-		if !future.Await(ctx, cs.Done(), cv.Done()) {
-			return nil
-		}
+func PutOnPlate(ctx context.Context, cs *future.Future[CookedSpaghetti], cv *future.Future[CookedVegetables]) *future.Future[Dish] {
+	return future.Join2(ctx, cs, cv, func(cs CookedSpaghetti, cv CookedVegetables) (Dish, error) {
 		Do("putting everything on plate")
-		d.Resolve(Dish("Dish:\n" + Format(cs.Get(), cv.Get())))
-		return nil
+		return Dish("Dish:\n" + Format(cs, cv)), nil
 	})
-	return d
 }
@@ -45,7 +45,7 @@ func main() {
 		defer wg.Done()
 		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 		defer cancel()
-		if future.Await(ctx, f.Done()) {
+		if future.Await(ctx, f) {
 			fmt.Println("Got result before timeout:", f.Get())
 		} else {
 			fmt.Println("Timed out!")
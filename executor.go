@@ -0,0 +1,196 @@
+package future
+
+import (
+	"sync"
+)
+
+// Executor runs submitted tasks, possibly asynchronously. Implementations decide when, where and with what degree of concurrency tasks are run.
+//
+// Executor implementations must be safe for concurrent use and must eventually run every submitted task.
+type Executor interface {
+	// Go submits task for execution.
+	Go(task func())
+}
+
+// ExecutorStats is a snapshot of the load of an executor.
+type ExecutorStats struct {
+	Workers int // maximum number of tasks the executor runs concurrently (for Weighted, its total capacity)
+	Busy    int // number of workers running tasks (for Weighted, the sum of weights of running tasks)
+	Queued  int // number of submitted tasks that are not started yet
+}
+
+// Utilization returns the fraction of workers that are busy, between 0 and 1.
+func (s ExecutorStats) Utilization() float64 {
+	if s.Workers <= 0 {
+		return 0
+	}
+	return min(float64(s.Busy)/float64(s.Workers), 1)
+}
+
+// MeasuredExecutor is an executor reporting its load. All executors of this package with bounded concurrency implement it.
+type MeasuredExecutor interface {
+	Executor
+
+	// Stats returns a snapshot of the load of the executor. Its fields are not read atomically together, so under load they may be slightly inconsistent with each other.
+	Stats() ExecutorStats
+}
+
+// Unbounded is an executor that runs every task in a new goroutine. It is the default executor used by helpers in this package.
+type Unbounded struct{}
+
+// Go runs task in a new goroutine.
+func (Unbounded) Go(task func()) {
+	go task()
+}
+
+// Inline is an executor that runs every task synchronously, in the goroutine that submits it.
+type Inline struct{}
+
+// Go runs task immediately, returning after it completes.
+func (Inline) Go(task func()) {
+	task()
+}
+
+// Pool is an executor that runs tasks on a fixed maximum number of goroutines. Tasks submitted while all workers are busy are queued and run in submission order.
+//
+// Workers are started on demand and exit when there is no more queued work, so an idle pool holds no goroutines and does not need to be closed.
+type Pool struct {
+	mu      sync.Mutex
+	size    int
+	running int
+	queue   []func()
+}
+
+// NewPool creates a new pool executor that runs at most size tasks concurrently. It panics if size is not positive.
+func NewPool(size int) *Pool {
+	if size < 1 {
+		panic("future: pool size must be positive")
+	}
+	return &Pool{size: size}
+}
+
+// Go queues task for execution on one of the pool workers.
+func (p *Pool) Go(task func()) {
+	p.mu.Lock()
+	p.queue = append(p.queue, task)
+	spawn := p.running < p.size
+	if spawn {
+		p.running++
+	}
+	p.mu.Unlock()
+	if spawn {
+		go p.work()
+	}
+}
+
+// Stats returns a snapshot of the load of the pool.
+func (p *Pool) Stats() ExecutorStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return ExecutorStats{Workers: p.size, Busy: p.running, Queued: len(p.queue)}
+}
+
+func (p *Pool) work() {
+	for {
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.queue = nil
+			p.running--
+			p.mu.Unlock()
+			return
+		}
+		task := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+		task()
+	}
+}
+
+// Weighted is an executor that bounds the total weight of concurrently running tasks, in the manner of a weighted semaphore. Every task runs in its own goroutine once enough capacity is available; tasks waiting for capacity are started in submission order.
+type Weighted struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiting []weightedTask
+}
+
+type weightedTask struct {
+	weight int64
+	task   func()
+}
+
+// NewWeighted creates a new weighted executor with the given total capacity. It panics if size is not positive.
+func NewWeighted(size int64) *Weighted {
+	if size < 1 {
+		panic("future: weighted executor size must be positive")
+	}
+	return &Weighted{size: size}
+}
+
+// Go submits task with weight of 1.
+func (w *Weighted) Go(task func()) {
+	w.GoWeighted(1, task)
+}
+
+// GoWeighted submits task with the given weight. The task is started once the sum of weights of all running tasks allows it. It panics if weight is not positive or exceeds the capacity of the executor.
+func (w *Weighted) GoWeighted(weight int64, task func()) {
+	if weight < 1 || weight > w.size {
+		panic("future: task weight out of range")
+	}
+	w.mu.Lock()
+	if len(w.waiting) > 0 || w.cur+weight > w.size {
+		w.waiting = append(w.waiting, weightedTask{weight: weight, task: task})
+		w.mu.Unlock()
+		return
+	}
+	w.cur += weight
+	w.mu.Unlock()
+	go w.run(weightedTask{weight: weight, task: task})
+}
+
+// Weight returns an executor that submits every task to w with the given weight.
+func (w *Weighted) Weight(weight int64) Executor {
+	return weightedExecutor{w: w, weight: weight}
+}
+
+// Stats returns a snapshot of the load of the executor, with weights of tasks as units of Workers and Busy.
+func (w *Weighted) Stats() ExecutorStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return ExecutorStats{Workers: int(w.size), Busy: int(w.cur), Queued: len(w.waiting)}
+}
+
+func (w *Weighted) run(t weightedTask) {
+	defer w.release(t.weight)
+	t.task()
+}
+
+func (w *Weighted) release(weight int64) {
+	w.mu.Lock()
+	w.cur -= weight
+	start := []weightedTask(nil)
+	for len(w.waiting) > 0 && w.cur+w.waiting[0].weight <= w.size {
+		t := w.waiting[0]
+		w.waiting[0] = weightedTask{}
+		w.waiting = w.waiting[1:]
+		w.cur += t.weight
+		start = append(start, t)
+	}
+	if len(w.waiting) == 0 {
+		w.waiting = nil
+	}
+	w.mu.Unlock()
+	for _, t := range start {
+		go w.run(t)
+	}
+}
+
+type weightedExecutor struct {
+	w      *Weighted
+	weight int64
+}
+
+func (we weightedExecutor) Go(task func()) {
+	we.w.GoWeighted(we.weight, task)
+}
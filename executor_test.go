@@ -0,0 +1,179 @@
+package future_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+type ConcurrencyMeter struct {
+	cur atomic.Int64
+	max atomic.Int64
+}
+
+func (m *ConcurrencyMeter) Enter(weight int64) {
+	c := m.cur.Add(weight)
+	for {
+		old := m.max.Load()
+		if c <= old || m.max.CompareAndSwap(old, c) {
+			return
+		}
+	}
+}
+
+func (m *ConcurrencyMeter) Leave(weight int64) {
+	m.cur.Add(-weight)
+}
+
+func (m *ConcurrencyMeter) Max() int64 {
+	return m.max.Load()
+}
+
+func RunMetered(ex future.Executor, m *ConcurrencyMeter, weight int64, tasks int) []*future.Future[int] {
+	gate := futuretest.NewStartCond()
+	defer gate.Start()
+	fs := make([]*future.Future[int], tasks)
+	for i := range tasks {
+		fs[i] = future.Go(func() int {
+			m.Enter(weight)
+			defer m.Leave(weight)
+			gate.Wait()
+			return i
+		}, future.WithExecutor(ex))
+	}
+	return fs
+}
+
+func AllResolvedInOrder(t *testing.T, fs []*future.Future[int]) {
+	t.Helper()
+	for i, f := range fs {
+		if got := f.Get(); got != i {
+			t.Errorf("future %d resolved with %d", i, got)
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(func() int { return 42 })
+	if got := f.Get(); got != 42 {
+		t.Errorf("go resolved with %d, expected 42", got)
+	}
+}
+
+func TestInline(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(func() int { return 42 }, future.WithExecutor(future.Inline{}))
+	if !futuretest.IsSuccessful(futuretest.GetResult(f, futuretest.IsDone)) {
+		t.Fatalf("future not resolved after inline execution")
+	}
+	if got := f.Get(); got != 42 {
+		t.Errorf("go resolved with %d, expected 42", got)
+	}
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	m := &ConcurrencyMeter{}
+	fs := RunMetered(future.NewPool(3), m, 1, 50)
+	AllResolvedInOrder(t, fs)
+	if m.Max() > 3 {
+		t.Errorf("pool ran %d tasks concurrently, expected at most 3", m.Max())
+	}
+}
+
+func TestPoolNested(t *testing.T) {
+	t.Parallel()
+
+	p := future.NewPool(1)
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	p.Go(func() {
+		defer wg.Done()
+		p.Go(wg.Done)
+	})
+	wg.Wait()
+}
+
+func TestWeighted(t *testing.T) {
+	t.Parallel()
+
+	w := future.NewWeighted(5)
+	m := &ConcurrencyMeter{}
+	fs1 := RunMetered(w.Weight(2), m, 2, 20)
+	fs2 := RunMetered(w, m, 1, 20)
+	AllResolvedInOrder(t, fs1)
+	AllResolvedInOrder(t, fs2)
+	if m.Max() > 5 {
+		t.Errorf("weighted executor ran tasks with total weight of %d concurrently, expected at most 5", m.Max())
+	}
+}
+
+func TestWeightedOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	w := future.NewWeighted(5)
+	for _, weight := range []int64{-1, 0, 6} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("submitting task with weight %d did not panic", weight)
+				}
+			}()
+			w.GoWeighted(weight, func() {})
+		}()
+	}
+}
+
+func TestExecutorStats(t *testing.T) {
+	t.Parallel()
+
+	executors := map[string]future.MeasuredExecutor{
+		"pool":          future.NewPool(2),
+		"weighted":      future.NewWeighted(2),
+		"work stealing": future.NewWorkStealing(2),
+		"scheduler":     future.NewScheduler(2),
+	}
+	for name, ex := range executors {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if s := ex.Stats(); s != (future.ExecutorStats{Workers: 2}) {
+				t.Errorf("idle executor stats are %+v, expected 2 idle workers", s)
+			}
+			started, release := make(chan struct{}, 5), make(chan struct{})
+			wg := &sync.WaitGroup{}
+			wg.Add(5)
+			for range 5 {
+				ex.Go(func() {
+					defer wg.Done()
+					started <- struct{}{}
+					<-release
+				})
+			}
+			<-started
+			<-started
+			if s := ex.Stats(); s != (future.ExecutorStats{Workers: 2, Busy: 2, Queued: 3}) {
+				t.Errorf("busy executor stats are %+v, expected 2 busy workers and 3 queued tasks", s)
+			}
+			if u := ex.Stats().Utilization(); u != 1 {
+				t.Errorf("busy executor utilization is %v, expected 1", u)
+			}
+			close(release)
+			wg.Wait()
+			for deadline := time.Now().Add(10 * time.Second); ex.Stats().Busy != 0 && time.Now().Before(deadline); {
+				time.Sleep(time.Millisecond) // workers exit shortly after running their last tasks
+			}
+			if s := ex.Stats(); s != (future.ExecutorStats{Workers: 2}) {
+				t.Errorf("executor stats after running all tasks are %+v, expected 2 idle workers", s)
+			}
+		})
+	}
+}
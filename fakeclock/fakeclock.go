@@ -0,0 +1,138 @@
+// Package fakeclock implements a future.Clock that advances only when told to, making tests of timing behavior (timeouts, retries, hedging) deterministic and instant.
+package fakeclock
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+var _ future.Clock = (*Clock)(nil)
+
+// Clock is a fake clock. Its time changes only when Advance is called, which also fires all timers whose deadlines have passed.
+type Clock struct {
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced whenever the set of timers changes
+	now     time.Time
+	seq     uint64
+	timers  []*Timer
+}
+
+// Timer is a single event scheduled with Clock.AfterFunc.
+type Timer struct {
+	clock *Clock
+	at    time.Time
+	seq   uint64
+	fn    func()
+}
+
+// New creates a new fake clock, set to the given time.
+func New(now time.Time) *Clock {
+	return &Clock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules fn to be called once the clock is advanced by at least the duration. Functions are called by the goroutine that advances the clock. If the duration is not positive, fn is called on the next Advance.
+func (c *Clock) AfterFunc(d time.Duration, fn func()) future.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	t := &Timer{clock: c, at: c.now.Add(d), seq: c.seq, fn: fn}
+	c.timers = append(c.timers, t)
+	c.notify()
+	return t
+}
+
+// Advance moves the clock forward by the duration, firing timers whose deadlines have passed. Timers are fired one by one, in the order of their deadlines (and then in the order they were scheduled in), with the clock set to the deadline of the timer being fired. Timers scheduled by fired functions are fired in the same call, if their deadlines have passed too.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		t := c.next(end)
+		if t == nil {
+			break
+		}
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+		c.mu.Unlock()
+		t.fn()
+		c.mu.Lock()
+	}
+	if end.After(c.now) { // fired functions may have advanced the clock further
+		c.now = end
+	}
+	c.mu.Unlock()
+}
+
+// Timers returns the number of scheduled timers that were neither fired nor stopped.
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until exactly n timers are scheduled. It allows tests to wait for code running in other goroutines to schedule its timers, before advancing the clock.
+func (c *Clock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		count, changed := len(c.timers), c.changed
+		c.mu.Unlock()
+		if count == n {
+			return
+		}
+		<-changed
+	}
+}
+
+// next removes and returns the earliest timer with deadline not after end, or nil if there is none.
+func (c *Clock) next(end time.Time) *Timer {
+	i := -1
+	for j, t := range c.timers {
+		if !t.at.After(end) && (i < 0 || t.before(c.timers[i])) {
+			i = j
+		}
+	}
+	if i < 0 {
+		return nil
+	}
+	t := c.timers[i]
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.notify()
+	return t
+}
+
+// notify wakes up goroutines blocked in BlockUntil. A channel is used instead of sync.Cond, as goroutines waiting on a condition variable are never considered idle by testing/synctest.
+func (c *Clock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Stop prevents the timer from firing. It returns false if the timer has already fired or been stopped.
+func (t *Timer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.notify()
+	return true
+}
+
+func (t *Timer) before(o *Timer) bool {
+	if t.at.Equal(o.at) {
+		return t.seq < o.seq
+	}
+	return t.at.Before(o.at)
+}
@@ -0,0 +1,91 @@
+package fakeclock_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // test constant
+
+type Fired struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (f *Fired) Record(c *fakeclock.Clock, name string) func() {
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.log = append(f.log, name+"@"+c.Now().Sub(epoch).String())
+	}
+}
+
+func (f *Fired) Must(t *testing.T, expected ...string) {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !slices.Equal(f.log, expected) {
+		t.Errorf("timers fired as %v, expected %v", f.log, expected)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	t.Parallel()
+
+	c := fakeclock.New(epoch)
+	fired := &Fired{}
+	c.AfterFunc(2*time.Second, fired.Record(c, "b"))
+	c.AfterFunc(time.Second, fired.Record(c, "a"))
+	c.AfterFunc(2*time.Second, fired.Record(c, "c"))
+	stopped := c.AfterFunc(time.Second, fired.Record(c, "never"))
+	c.AfterFunc(time.Hour, fired.Record(c, "later"))
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Errorf("stop returned false for scheduled timer or true for stopped one")
+	}
+	c.Advance(time.Second - time.Nanosecond)
+	fired.Must(t)
+	c.Advance(time.Minute)
+	fired.Must(t, "a@1s", "b@2s", "c@2s")
+	if now := c.Now().Sub(epoch); now != time.Minute+time.Second-time.Nanosecond {
+		t.Errorf("clock advanced to %v, expected 1m0.999999999s", now)
+	}
+	if n := c.Timers(); n != 1 {
+		t.Errorf("clock has %d scheduled timers, expected 1", n)
+	}
+}
+
+func TestAdvanceChained(t *testing.T) {
+	t.Parallel()
+
+	c := fakeclock.New(epoch)
+	fired := &Fired{}
+	c.AfterFunc(time.Second, func() {
+		fired.Record(c, "a")()
+		c.AfterFunc(time.Second, fired.Record(c, "b"))
+		c.AfterFunc(time.Hour, fired.Record(c, "later"))
+	})
+	c.Advance(3 * time.Second)
+	fired.Must(t, "a@1s", "b@2s")
+}
+
+func TestBlockUntil(t *testing.T) {
+	t.Parallel()
+
+	c := fakeclock.New(epoch)
+	f := future.WithTimeout(&future.Future[int]{}, time.Second, future.WithClock(c))
+	go func() {
+		future.WithTimeout(&future.Future[int]{}, time.Second, future.WithClock(c))
+	}()
+	c.BlockUntil(2)
+	c.Advance(time.Second)
+	c.BlockUntil(0)
+	if f.Err() == nil {
+		t.Errorf("future with timeout not rejected after clock advanced")
+	}
+}
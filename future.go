@@ -8,28 +8,80 @@ import (
 
 // Future is a wrapper that allows return a result of an asynchronous operation at some point in the future.
 //
+// A future is resolved exactly once, either with a value (see Resolve) or with an error (see Reject).
+//
 // Futures are similar to channels with capacity of 1, with a notable difference that futures cannot be closed (unlike channels) and they store value, making them easier to use for single value broadcasts.
 type Future[T any] struct {
-	vp atomic.Pointer[T]             // value pointer
-	dp atomic.Pointer[chan struct{}] // done pointer
+	state atomic.Uint32                 // resolvement state
+	dp    atomic.Pointer[chan struct{}] // done pointer, installed lazily by the first waiter
+	tp    atomic.Pointer[pendingTask]   // pending task pointer
+	cp    atomic.Pointer[callback]      // callbacks pointer
+	m     *meta                         // debugging information, nil unless created while debugging (see New)
+	rp    bool                          // whether Get repanics (see WithRepanic)
+	v     T                             // value, valid once state is stateResolved
+	err   error                         // error, valid once state is stateResolved
 }
 
-// Resolved creates a new future that is already resolved with the provided value.
-func Resolved[T any](v T) *Future[T] {
-	f := &Future[T]{}
-	f.vp.Store(&v)
+// Future states. Value and error are written by the only goroutine that managed to move the state from pending to resolving, before the state is moved to resolved.
+const (
+	statePending uint32 = iota
+	stateResolving
+	stateResolved
+)
+
+// closedDone is a closed channel, installed as the done channel of futures that are resolved before anyone waits on them.
+var closedDone = func() *chan struct{} { //nolint:gochecknoglobals // sentinel value
 	d := make(chan struct{})
 	close(d)
-	f.dp.Store(&d)
+	return &d
+}()
+
+// Resolved creates a new future that is already resolved with the provided value.
+func Resolved[T any](v T) *Future[T] {
+	f := &Future[T]{v: v}
+	f.markResolved()
 	return f
 }
 
+// Rejected creates a new future that is already rejected with the provided error. It panics if err is nil.
+func Rejected[T any](err error) *Future[T] {
+	if err == nil {
+		panic("future: rejected with nil error")
+	}
+	f := &Future[T]{err: err}
+	f.markResolved()
+	return f
+}
+
+// NewBatch creates n new, pending futures, backed by a single allocation. It is meant for hot paths that create many futures at once (like request scoped fan-outs) - the futures behave exactly the same as separately allocated ones, but the memory of the whole batch is reclaimed only once none of them is referenced anymore.
+//
+// Futures are not pooled for reuse, because there is no way to know when all readers of a resolved future are gone.
+func NewBatch[T any](n int) []*Future[T] {
+	backing := make([]Future[T], n)
+	fs := make([]*Future[T], n)
+	for i := range backing {
+		fs[i] = &backing[i]
+	}
+	return fs
+}
+
+func (f *Future[T]) markResolved() {
+	f.state.Store(stateResolved)
+	f.dp.Store(closedDone)
+	f.cp.Store(settledCallbacks)
+}
+
 func (f *Future[T]) done() chan struct{} {
 	if dp := f.dp.Load(); dp != nil {
 		return *dp
 	}
+	if f.m != nil {
+		f.m.observed.Store(true)
+	}
 	d := make(chan struct{})
-	f.dp.CompareAndSwap(nil, &d)
+	if f.dp.CompareAndSwap(nil, &d) {
+		return d
+	}
 	return *f.dp.Load()
 }
 
@@ -42,41 +94,191 @@ func (f *Future[T]) Resolve(v T) {
 
 // TryResolve attempts to resolve the given future with the provided value. It returns false if the future was already resolved, otherwise it resolves it with the provided value and returns true.
 func (f *Future[T]) TryResolve(v T) bool {
-	if !f.vp.CompareAndSwap(nil, &v) {
+	return f.trySettle(v, nil)
+}
+
+// Reject resolves the future with the provided error instead of a value. It panics if the future was already resolved or if err is nil.
+func (f *Future[T]) Reject(err error) {
+	if !f.TryReject(err) {
+		panic("future: already resolved")
+	}
+}
+
+// TryReject attempts to resolve the given future with the provided error instead of a value. It returns false if the future was already resolved, otherwise it rejects it with the provided error and returns true. It panics if err is nil.
+func (f *Future[T]) TryReject(err error) bool {
+	if err == nil {
+		panic("future: rejected with nil error")
+	}
+	var z T
+	return f.trySettle(z, err)
+}
+
+func (f *Future[T]) trySettle(v T, err error) bool {
+	return f.complete(v, err, false)
+}
+
+// tryCancel rejects the future with the cause of a cancellation, like a dropped task or a cancelled context.
+func (f *Future[T]) tryCancel(cause error) bool {
+	var z T
+	return f.complete(z, cause, true)
+}
+
+// complete stores the result without allocating. The done channel is closed only if some waiter installed it, otherwise the closedDone sentinel takes its place, so that waiters arriving later never block.
+func (f *Future[T]) complete(v T, err error, cancelled bool) bool {
+	if !f.state.CompareAndSwap(statePending, stateResolving) {
 		return false
 	}
-	close(f.done())
+	f.v, f.err = v, err
+	f.state.Store(stateResolved)
+	if f.m != nil {
+		f.m.result = result[T]{v: v, err: err}
+		f.m.settle(err, cancelled)
+	}
+	if f.tp.Load() != nil {
+		f.tp.Store(nil)
+	}
+	if dp := f.dp.Swap(closedDone); dp != nil {
+		close(*dp)
+	}
+	f.runCallbacks(nil)
 	return true
 }
 
-// Get awaits for the resolvement of the given future and returns its value.
+func (f *Future[T]) resolved() bool {
+	return f.state.Load() == stateResolved
+}
+
+// Get awaits for the resolvement of the given future and returns its value. If the future was rejected, the zero value of T is returned, unless the future was created with WithRepanic option and rejected with a *PanicError, in which case Get panics with it.
 func (f *Future[T]) Get() T {
-	if vp := f.vp.Load(); vp != nil {
-		return *vp
+	rp := f.rp
+	v, err := f.await()
+	if err != nil && rp {
+		repanic(err)
 	}
-	f.Wait()
-	return *f.vp.Load()
+	return v
+}
+
+// Err awaits for the resolvement of the given future and returns the error it was rejected with, or nil if it was resolved with a value.
+func (f *Future[T]) Err() error {
+	_, err := f.await()
+	return err
 }
 
-// Wait awaits for the resolvement of the given future.
+// Result awaits for the resolvement of the given future and returns both its value and the error it was rejected with.
+func (f *Future[T]) Result() (T, error) {
+	return f.await()
+}
+
+// Wait awaits for the resolvement of the given future. If the future was created by Go with an executor that allows it (see WorkStealing) and its task was not started yet, the task is run in the calling goroutine instead.
 func (f *Future[T]) Wait() {
+	_, _ = f.await()
+}
+
+// await waits for the resolvement of the future and returns its result. Futures with debugging information are not referenced while blocked - the result is read from the debugging information instead - so that leak detection notices them being collected while awaited.
+func (f *Future[T]) await() (T, error) {
+	if f.resolved() {
+		return f.v, f.err
+	}
+	if tp := f.tp.Load(); tp != nil {
+		tp.ex.await(tp, f.done())
+	}
+	if m := f.m; m != nil {
+		d, done := f.done(), m.wait()
+		<-d
+		done()
+		r := m.result.(result[T]) //nolint:forcetypeassert // stored before the done channel is closed
+		return r.v, r.err
+	}
 	<-f.done()
+	return f.v, f.err
 }
 
 // Done returns channel that will be closed when the given future is resolved.
 func (f *Future[T]) Done() <-chan struct{} {
+	if f.resolved() {
+		return *closedDone
+	}
 	return f.done()
 }
 
-// Await waits for either the given context to be cancelled - in which case the function returns false - or for all of the supplied struct channels to be closed - in which case the function returns true.
-func Await(ctx context.Context, chs ...<-chan struct{}) bool {
-	if len(chs) == 0 {
-		return ctx.Err() == nil
+// Then returns a future that, once f is resolved, is resolved with the result of fn called with the value of f. The function is run using the configured executor (by default in a new goroutine). If f is rejected, fn is not called and the returned future is rejected with the same error, also by a task of the executor. If fn panics, the returned future is rejected with a *PanicError.
+func Then[T, R any](f *Future[T], fn func(T) (R, error), opts ...Option) *Future[R] {
+	o := newOptions(opts)
+	r := newFuture[R](context.Background(), o, f.m)
+	f.afterResolve(func() {
+		v, err := f.Result()
+		if err != nil {
+			o.submit(func() { r.Reject(err) }, r.cancel) // passed through as a task too, like promises do
+			return
+		}
+		labels := o.labels()
+		o.submit(func() {
+			r.m.run(labels, func() {
+				r.settle(call(func() (R, error) { return fn(v) }))
+			})
+		}, r.cancel)
+	})
+	return r
+}
+
+// Catch returns a future that, once f is resolved, is resolved with the value of f or, if f was rejected, with the result of fn called with the error of f. Both the function and resolving with the value of f are run using the configured executor (by default in a new goroutine). If fn panics, the returned future is rejected with a *PanicError.
+func Catch[T any](f *Future[T], fn func(error) (T, error), opts ...Option) *Future[T] {
+	o := newOptions(opts)
+	r := newFuture[T](context.Background(), o, f.m)
+	f.afterResolve(func() {
+		v, err := f.Result()
+		if err == nil {
+			o.submit(func() { r.Resolve(v) }, r.cancel) // passed through as a task too, like promises do
+			return
+		}
+		labels := o.labels()
+		o.submit(func() {
+			r.m.run(labels, func() {
+				r.settle(call(func() (T, error) { return fn(err) }))
+			})
+		}, r.cancel)
+	})
+	return r
+}
+
+func (f *Future[T]) settle(v T, err error) {
+	if err != nil {
+		f.Reject(err)
+		return
 	}
-	select {
-	case <-ctx.Done():
-		return false
-	case <-chs[0]:
-		return Await(ctx, chs[1:]...)
+	f.Resolve(v)
+}
+
+func (f *Future[T]) reject(err error) {
+	f.Reject(err)
+}
+
+func (f *Future[T]) cancel(cause error) {
+	if !f.tryCancel(cause) {
+		panic("future: already resolved")
+	}
+}
+
+// Go runs fn using the configured executor (by default in a new goroutine) and returns a future that is resolved with its result. If the task is dropped instead of being run (for example because its deadline has passed), the future is rejected with the cause. If fn panics, the future is rejected with a *PanicError.
+func Go[T any](fn func() T, opts ...Option) *Future[T] {
+	o := newOptions(opts)
+	f := newFuture[T](context.Background(), o, nil)
+	body := func() {
+		f.settle(call(func() (T, error) { return fn(), nil }))
+	}
+	labels := o.labels()
+	run := func() {
+		f.m.run(labels, body)
 	}
+	if ie, ok := o.executor.(inliningExecutor); ok && o.deadline.IsZero() {
+		t := ie.newPending(run)
+		t.inline = func() {
+			f.m.run(false, body) // the waiting goroutine keeps its own labels
+		}
+		f.tp.Store(t)
+		ie.goPending(t)
+		return f
+	}
+	o.submit(run, f.cancel)
+	return f
 }
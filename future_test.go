@@ -2,300 +2,143 @@ package future_test
 
 import (
 	"context"
-	"fmt"
-	"strings"
-	"sync"
+	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
 )
 
-type Result[T any] struct {
-	Value   T
-	Success bool
-	Panic   bool
-}
-
-func GetResult[T any](f *future.Future[T], fn func(*future.Future[T]) (T, bool)) (r *Result[T]) {
-	defer func() {
-		if rec := recover(); rec != nil {
-			r = &Result[T]{Panic: true}
-			return
-		}
-	}()
-	v, ok := fn(f)
-	r = &Result[T]{Value: v, Success: ok, Panic: false}
-	return
-}
+func TestFuture(t *testing.T) {
+	t.Parallel()
 
-func (r *Result[T]) String() string {
-	return fmt.Sprintf("{Value:%v,Success:%v,Panic:%v}", r.Value, r.Success, r.Panic)
-}
+	f := &future.Future[int]{}
 
-type StartCond struct {
-	start chan struct{}
-}
+	preStart, start, postStart := futuretest.NewStartCond(), futuretest.NewStartCond(), futuretest.NewStartCond()
+	preStart.Start()
 
-func NewStartCond() StartCond {
-	return StartCond{start: make(chan struct{})}
-}
+	resolvers := futuretest.NewResults(start, f, futuretest.Resolve(11), futuretest.Resolve(12), futuretest.Resolve(13), futuretest.Resolve(14), futuretest.Resolve(15))
+	tryResolvers := futuretest.NewResults(start, f, futuretest.TryResolve(21), futuretest.TryResolve(22), futuretest.TryResolve(23), futuretest.TryResolve(24), futuretest.TryResolve(25))
 
-func (sc StartCond) Start() {
-	close(sc.start)
-}
+	preIsDone := futuretest.NewResults(preStart, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
+	preGot := futuretest.NewResults(preStart, f, futuretest.Get, futuretest.Get, futuretest.Get)
+	got := futuretest.NewResults(start, f, futuretest.Get, futuretest.Get, futuretest.Get)
+	gotWaited := futuretest.NewResults(start, f, futuretest.WaitAndGet, futuretest.WaitAndGet, futuretest.WaitAndGet)
+	postIsDone := futuretest.NewResults(postStart, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
 
-func (sc StartCond) Wait() {
-	<-sc.start
-}
+	futuretest.All(futuretest.IsUnsuccessful, preIsDone)
 
-type Results[T any] struct {
-	wg        *sync.WaitGroup
-	collected []*Result[T]
-}
+	start.Start()
 
-func NewResults[T any](sc StartCond, f *future.Future[T], fns ...func(*future.Future[T]) (T, bool)) *Results[T] {
-	r := &Results[T]{
-		wg:        &sync.WaitGroup{},
-		collected: make([]*Result[T], len(fns)),
+	if futuretest.One(futuretest.IsSuccessful, resolvers) {
+		futuretest.AllExceptOneMust(t, futuretest.IsPanic, resolvers)
+		futuretest.AllMust(t, futuretest.IsUnsuccessful, tryResolvers)
+	} else {
+		futuretest.AllMust(t, futuretest.IsPanic, resolvers)
+		futuretest.AllExceptOneMust(t, futuretest.IsUnsuccessful, tryResolvers)
 	}
+	v := futuretest.FindOne(t, futuretest.IsSuccessful, resolvers, tryResolvers).Value
 
-	pre := NewStartCond()
-	defer pre.Start()
+	futuretest.AllMust(t, futuretest.IsSuccessful, preGot, got, gotWaited)
+	futuretest.AllMust(t, futuretest.IsValueEqual(v), preGot, got, gotWaited)
 
-	for i, fn := range fns {
-		r.wg.Add(1)
-		go func() {
-			defer r.wg.Done()
-			pre.Wait()
-			sc.Wait()
-			r.collected[i] = GetResult(f, fn)
-		}()
-	}
+	postStart.Start()
 
-	return r
+	futuretest.AllMust(t, futuretest.IsSuccessful, postIsDone)
 }
 
-func (r *Results[T]) Range(yield func(*Result[T]) bool) {
-	r.wg.Wait()
-	for _, x := range r.collected {
-		if !yield(x) {
-			return
-		}
-	}
-}
-
-func (r *Results[T]) String() string {
-	strs := []string{}
-	for x := range r.Range {
-		strs = append(strs, x.String())
-	}
-	return "[" + strings.Join(strs, ", ") + "]"
-}
+func TestResolved(t *testing.T) {
+	t.Parallel()
 
-func Count[T any](test func(*Result[T]) bool, results ...*Results[T]) (count, totalCount int) {
-	for _, res := range results {
-		for r := range res.Range {
-			if test(r) {
-				count++
-			}
-			totalCount++
-		}
-	}
-	return count, totalCount
-}
+	f := future.Resolved(1)
 
-func FindOne[T any](t *testing.T, test func(*Result[T]) bool, results ...*Results[T]) *Result[T] {
-	t.Helper()
-	c, _ := Count(test, results...)
-	if c == 0 {
-		t.Fatalf("no result that passes test found: %v", results)
-		return nil
-	}
-	if c > 1 {
-		t.Fatalf("more than one result that passes test found: %v", results)
-		return nil
-	}
-	for _, res := range results {
-		for r := range res.Range {
-			if test(r) {
-				return r
-			}
-		}
-	}
-	t.Fatalf("count returned 1, but search found no matching results: %v", results)
-	return nil
-}
+	preStart, start, postStart := futuretest.NewStartCond(), futuretest.NewStartCond(), futuretest.NewStartCond()
+	preStart.Start()
 
-func All[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
-	c, all := Count(test, results...)
-	return c == all
-}
+	resolvers := futuretest.NewResults(start, f, futuretest.Resolve(11), futuretest.Resolve(12), futuretest.Resolve(13), futuretest.Resolve(14), futuretest.Resolve(15))
+	tryResolvers := futuretest.NewResults(start, f, futuretest.TryResolve(21), futuretest.TryResolve(22), futuretest.TryResolve(23), futuretest.TryResolve(24), futuretest.TryResolve(25))
 
-func AllMust[T any](t *testing.T, test func(*Result[T]) bool, results ...*Results[T]) {
-	t.Helper()
-	if !All(test, results...) {
-		t.Errorf("all must pass: %v", results)
-	}
-}
+	preIsDone := futuretest.NewResults(preStart, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
+	preGot := futuretest.NewResults(preStart, f, futuretest.Get, futuretest.Get, futuretest.Get)
+	isDone := futuretest.NewResults(start, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
+	got := futuretest.NewResults(start, f, futuretest.Get, futuretest.Get, futuretest.Get)
+	gotWaited := futuretest.NewResults(start, f, futuretest.WaitAndGet, futuretest.WaitAndGet, futuretest.WaitAndGet)
+	postIsDone := futuretest.NewResults(postStart, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
 
-func AllExceptOne[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
-	c, all := Count(test, results...)
-	return all-c == 1
-}
+	futuretest.AllMust(t, futuretest.IsSuccessful, preIsDone)
+	futuretest.AllMust(t, futuretest.IsSuccessful, preGot)
+	futuretest.AllMust(t, futuretest.IsValueEqual(1), preGot)
 
-func AllExceptOneMust[T any](t *testing.T, test func(*Result[T]) bool, results ...*Results[T]) {
-	t.Helper()
-	if !AllExceptOne(test, results...) {
-		t.Errorf("all except one must pass: %v", results)
-	}
-}
+	start.Start()
 
-func One[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
-	c, _ := Count(test, results...)
-	return c == 1
-}
+	futuretest.AllMust(t, futuretest.IsPanic, resolvers)
+	futuretest.AllMust(t, futuretest.IsUnsuccessful, tryResolvers)
 
-func OneMust[T any](t *testing.T, test func(*Result[T]) bool, results ...*Results[T]) {
-	t.Helper()
-	if !One(test, results...) {
-		t.Errorf("exactly one must pass: %v", results)
-	}
-}
+	futuretest.AllMust(t, futuretest.IsSuccessful, isDone)
+	futuretest.AllMust(t, futuretest.IsSuccessful, preGot, got, gotWaited)
+	futuretest.AllMust(t, futuretest.IsValueEqual(1), preGot, got, gotWaited)
 
-// func None[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
-// 	c, _ := Count(test, results...)
-// 	return c == 0
-// }
-
-// func NoneMust[T any](t *testing.T, test func(*Result[T]) bool, results ...*Results[T]) {
-// 	t.Helper()
-// 	if !One(test, results...) {
-// 		t.Errorf("all must fail: %v", results)
-// 	}
-// }
-
-func TryResolve[T any](v T) func(*future.Future[T]) (T, bool) {
-	return func(f *future.Future[T]) (T, bool) {
-		return v, f.TryResolve(v)
-	}
-}
+	postStart.Start()
 
-func Resolve[T any](v T) func(*future.Future[T]) (T, bool) {
-	return func(f *future.Future[T]) (T, bool) {
-		f.Resolve(v)
-		return v, true
-	}
+	futuretest.AllMust(t, futuretest.IsSuccessful, postIsDone)
 }
 
-func IsDone[T any](f *future.Future[T]) (T, bool) {
-	var z T
-	select {
-	case <-f.Done():
-		return z, true
-	default:
-		return z, false
-	}
-}
+var errTest = errors.New("test error")
 
-func Get[T any](f *future.Future[T]) (T, bool) {
-	return f.Get(), true
-}
+func TestRejected(t *testing.T) {
+	t.Parallel()
 
-func WaitAndGet[T any](f *future.Future[T]) (T, bool) {
-	f.Wait()
-	return f.Get(), true
-}
+	f := future.Rejected[int](errTest)
 
-func IsValueEqual[T comparable](to T) func(*Result[T]) bool {
-	return func(r *Result[T]) bool {
-		return r.Panic == false && r.Value == to
-	}
-}
+	start := futuretest.NewStartCond()
 
-func IsSuccessful[T any](r *Result[T]) bool {
-	return r.Panic == false && r.Success == true
-}
+	resolvers := futuretest.NewResults(start, f, futuretest.Resolve(11), futuretest.Reject[int](errTest))
+	tryResolvers := futuretest.NewResults(start, f, futuretest.TryResolve(21), futuretest.TryReject[int](errTest))
+	isDone := futuretest.NewResults(start, f, futuretest.IsDone, futuretest.IsDone, futuretest.IsDone)
+	got := futuretest.NewResults(start, f, futuretest.Get, futuretest.Get, futuretest.Get)
+	errs := futuretest.NewResults(start, f, futuretest.ErrIs[int](errTest), futuretest.ErrIs[int](errTest))
 
-func IsUnsuccessful[T any](r *Result[T]) bool {
-	return r.Panic == false && r.Success == false
-}
+	start.Start()
 
-func IsPanic[T any](r *Result[T]) bool {
-	return r.Panic == true
+	futuretest.AllMust(t, futuretest.IsPanic, resolvers)
+	futuretest.AllMust(t, futuretest.IsUnsuccessful, tryResolvers)
+	futuretest.AllMust(t, futuretest.IsSuccessful, isDone, got, errs)
+	futuretest.AllMust(t, futuretest.IsValueEqual(0), got)
 }
 
-func TestFuture(t *testing.T) {
+func TestReject(t *testing.T) {
 	t.Parallel()
 
 	f := &future.Future[int]{}
 
-	preStart, start, postStart := NewStartCond(), NewStartCond(), NewStartCond()
-	preStart.Start()
-
-	resolvers := NewResults(start, f, Resolve(11), Resolve(12), Resolve(13), Resolve(14), Resolve(15))
-	tryResolvers := NewResults(start, f, TryResolve(21), TryResolve(22), TryResolve(23), TryResolve(24), TryResolve(25))
-
-	preIsDone := NewResults(preStart, f, IsDone, IsDone, IsDone)
-	preGot := NewResults(preStart, f, Get, Get, Get)
-	got := NewResults(start, f, Get, Get, Get)
-	gotWaited := NewResults(start, f, WaitAndGet, WaitAndGet, WaitAndGet)
-	postIsDone := NewResults(postStart, f, IsDone, IsDone, IsDone)
+	start := futuretest.NewStartCond()
 
-	All(IsUnsuccessful, preIsDone)
+	rejecters := futuretest.NewResults(start, f, futuretest.TryReject[int](errTest), futuretest.TryReject[int](errTest), futuretest.TryReject[int](errTest))
+	got := futuretest.NewResults(start, f, futuretest.WaitAndGet, futuretest.WaitAndGet, futuretest.WaitAndGet)
+	errs := futuretest.NewResults(start, f, futuretest.ErrIs[int](errTest), futuretest.ErrIs[int](errTest))
 
 	start.Start()
 
-	if One(IsSuccessful, resolvers) {
-		AllExceptOneMust(t, IsPanic, resolvers)
-		AllMust(t, IsUnsuccessful, tryResolvers)
-	} else {
-		AllMust(t, IsPanic, resolvers)
-		AllExceptOneMust(t, IsUnsuccessful, tryResolvers)
-	}
-	v := FindOne(t, IsSuccessful, resolvers, tryResolvers).Value
-
-	AllMust(t, IsSuccessful, preGot, got, gotWaited)
-	AllMust(t, IsValueEqual(v), preGot, got, gotWaited)
-
-	postStart.Start()
+	futuretest.AllExceptOneMust(t, futuretest.IsUnsuccessful, rejecters)
+	futuretest.AllMust(t, futuretest.IsSuccessful, got, errs)
+	futuretest.AllMust(t, futuretest.IsValueEqual(0), got)
 
-	AllMust(t, IsSuccessful, postIsDone)
+	if v, err := f.Result(); v != 0 || !errors.Is(err, errTest) {
+		t.Errorf("result returned (%v, %v), expected (0, %v)", v, err, errTest)
+	}
 }
 
-func TestResolved(t *testing.T) {
+func TestRejectNil(t *testing.T) {
 	t.Parallel()
 
-	f := future.Resolved(1)
-
-	preStart, start, postStart := NewStartCond(), NewStartCond(), NewStartCond()
-	preStart.Start()
-
-	resolvers := NewResults(start, f, Resolve(11), Resolve(12), Resolve(13), Resolve(14), Resolve(15))
-	tryResolvers := NewResults(start, f, TryResolve(21), TryResolve(22), TryResolve(23), TryResolve(24), TryResolve(25))
-
-	preIsDone := NewResults(preStart, f, IsDone, IsDone, IsDone)
-	preGot := NewResults(preStart, f, Get, Get, Get)
-	isDone := NewResults(start, f, IsDone, IsDone, IsDone)
-	got := NewResults(start, f, Get, Get, Get)
-	gotWaited := NewResults(start, f, WaitAndGet, WaitAndGet, WaitAndGet)
-	postIsDone := NewResults(postStart, f, IsDone, IsDone, IsDone)
-
-	AllMust(t, IsSuccessful, preIsDone)
-	AllMust(t, IsSuccessful, preGot)
-	AllMust(t, IsValueEqual(1), preGot)
-
-	start.Start()
-
-	AllMust(t, IsPanic, resolvers)
-	AllMust(t, IsUnsuccessful, tryResolvers)
-
-	AllMust(t, IsSuccessful, isDone)
-	AllMust(t, IsSuccessful, preGot, got, gotWaited)
-	AllMust(t, IsValueEqual(1), preGot, got, gotWaited)
-
-	postStart.Start()
-
-	AllMust(t, IsSuccessful, postIsDone)
+	f := &future.Future[int]{}
+	if !futuretest.IsPanic(futuretest.GetResult(f, futuretest.TryReject[int](nil))) {
+		t.Errorf("rejecting with nil error did not panic")
+	}
+	if futuretest.IsSuccessful(futuretest.GetResult(f, futuretest.IsDone)) {
+		t.Errorf("future resolved after rejecting with nil error")
+	}
 }
 
 func TestAwaitSuccessful(t *testing.T) {
@@ -307,20 +150,20 @@ func TestAwaitSuccessful(t *testing.T) {
 
 	got := &future.Future[bool]{}
 	go func() {
-		got.Resolve(future.Await(ctx, doneA, doneB, doneC))
+		got.Resolve(future.Await(ctx, future.FromSignal(doneA), future.FromSignal(doneB), future.FromSignal(doneC)))
 	}()
 
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
 	close(doneB)
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
 	close(doneA)
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
@@ -339,20 +182,20 @@ func TestAwaitCancel(t *testing.T) {
 
 	got := &future.Future[bool]{}
 	go func() {
-		got.Resolve(future.Await(ctx, doneA, doneB, doneC))
+		got.Resolve(future.Await(ctx, future.FromSignal(doneA), future.FromSignal(doneB), future.FromSignal(doneC)))
 	}()
 
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
 	close(doneB)
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
 	close(doneA)
-	if IsSuccessful(GetResult(got, IsDone)) {
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
 		t.Errorf("await returned %v before closing all channels", got.Get())
 	}
 
@@ -361,3 +204,138 @@ func TestAwaitCancel(t *testing.T) {
 		t.Errorf("await returned %v after context cancel", got.Get())
 	}
 }
+
+func TestThen(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	got := future.Then(f, func(v int) (string, error) { return strconv.Itoa(v), nil })
+	failed := future.Then(got, func(string) (int, error) { return 0, errTest })
+	recovered := future.Catch(failed, func(err error) (int, error) { return 2, nil })
+	f.Resolve(1)
+
+	if v, err := got.Result(); v != "1" || err != nil {
+		t.Errorf("then resolved with (%v, %v), expected (1, nil)", v, err)
+	}
+	if err := failed.Err(); !errors.Is(err, errTest) {
+		t.Errorf("then rejected with %v, expected %v", err, errTest)
+	}
+	if v, err := recovered.Result(); v != 2 || err != nil {
+		t.Errorf("catch resolved with (%v, %v), expected (2, nil)", v, err)
+	}
+}
+
+func TestAllocations(t *testing.T) { //nolint:paralleltest // allocations cannot be measured in parallel tests
+	// futures are preallocated, so that only allocations made by the operations under test are counted
+	var fs []future.Future[int]
+	var batch []*future.Future[int]
+	next := func() *future.Future[int] {
+		f := &fs[0]
+		fs = fs[1:]
+		return f
+	}
+
+	cases := []struct {
+		name     string
+		fn       func()
+		expected float64
+	}{
+		{"Resolve and Get", func() { f := next(); f.Resolve(1); f.Get() }, 0},
+		{"Resolve and Wait", func() { f := next(); f.Resolve(1); f.Wait() }, 0},
+		{"Resolve and Done", func() { f := next(); f.Resolve(1); <-f.Done() }, 0},
+		{"Reject and Result", func() { f := next(); f.Reject(errTest); _, _ = f.Result() }, 0},
+		{"TryResolve resolved", func() { f := next(); f.Resolve(1); f.TryResolve(2) }, 0},
+		{"Resolved and Get", func() { future.Resolved(1).Get() }, 1},
+		{"NewBatch", func() { batch = future.NewBatch[int](100) }, 2},
+	}
+	for _, c := range cases {
+		fs = make([]future.Future[int], 101)
+		if allocs := testing.AllocsPerRun(100, c.fn); allocs != c.expected {
+			t.Errorf("%s: %v allocations per run, expected %v", c.name, allocs, c.expected)
+		}
+	}
+	_ = batch
+}
+
+func TestNewBatch(t *testing.T) {
+	t.Parallel()
+
+	fs := future.NewBatch[int](10)
+	start := futuretest.NewStartCond()
+	results := make([]*futuretest.Results[int], len(fs))
+	for i, f := range fs {
+		results[i] = futuretest.NewResults(start, f, futuretest.WaitAndGet, futuretest.WaitAndGet)
+	}
+	start.Start()
+	for i, f := range fs {
+		f.Resolve(i)
+	}
+	for i, f := range fs {
+		futuretest.AllMust(t, futuretest.IsValueEqual(i), results[i])
+		if !futuretest.IsPanic(futuretest.GetResult(f, futuretest.Resolve(0))) {
+			t.Errorf("resolving already resolved future %d from batch did not panic", i)
+		}
+	}
+}
+
+func BenchmarkResolveGet(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		f := &future.Future[int]{}
+		f.Resolve(1)
+		f.Get()
+	}
+}
+
+func BenchmarkBatchResolveGet(b *testing.B) {
+	for _, n := range []int{1, 10, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				for i, f := range future.NewBatch[int](n) {
+					f.Resolve(i)
+					f.Get()
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkResolveRejectResult(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		f := &future.Future[int]{}
+		f.Reject(errTest)
+		_, _ = f.Result()
+	}
+}
+
+func BenchmarkResolvedGet(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		future.Resolved(1).Get()
+	}
+}
+
+func BenchmarkDoneResolve(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		f := &future.Future[int]{}
+		d := f.Done()
+		f.Resolve(1)
+		<-d
+	}
+}
+
+func BenchmarkResolveWaitParallel(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		f := &future.Future[int]{}
+		got := make(chan int)
+		go func() {
+			got <- f.Get()
+		}()
+		f.Resolve(1)
+		<-got
+	}
+}
@@ -0,0 +1,114 @@
+// Package futuredebug serves the registry of live futures (see future.EnableRegistry) over HTTP, similarly to /debug/requests of golang.org/x/net/trace, so that during an incident it is possible to see what every stuck goroutine is waiting on.
+package futuredebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+// Entry is a single future, as rendered by the handler.
+type Entry struct {
+	ID      uint64        `json:"id"`
+	Name    string        `json:"name,omitempty"`
+	State   string        `json:"state"`
+	Created time.Time     `json:"created"`
+	Age     time.Duration `json:"age"`
+	Waiters int           `json:"waiters"`
+	Stack   string        `json:"stack"`
+
+	Waiting   []uint64 `json:"waiting,omitempty"`   // goroutines blocked waiting for the future
+	Producer  uint64   `json:"producer,omitempty"`  // goroutine expected to resolve the future
+	DependsOn []uint64 `json:"dependsOn,omitempty"` // futures the future is resolved from
+}
+
+// Page is the whole listing, as rendered by the handler.
+type Page struct {
+	Enabled   bool     `json:"enabled"`   // whether the registry is enabled
+	All       bool     `json:"all"`       // whether resolved and rejected futures are listed too
+	Entries   []Entry  `json:"entries"`   // listed futures, oldest first
+	Deadlocks []string `json:"deadlocks"` // cycles in the wait-for graph (see Deadlocks)
+}
+
+// Handler returns a handler listing futures in the registry, along with deadlocks found in their wait-for graph. By default only pending futures are listed, and futures that are settled but not yet garbage collected are listed too if the "all" query parameter is set. The listing is rendered as HTML, or as JSON if the "format" query parameter is "json" or the request accepts application/json. If the "format" query parameter is "dot", the wait-for graph is rendered instead (see Dump).
+//
+// The handler does not enable the registry - use future.EnableRegistry for that.
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+// Snapshot returns the listing, the way the handler renders it.
+func Snapshot(all bool) Page {
+	infos := future.Registered()
+	p := Page{Enabled: infos != nil, All: all, Entries: []Entry{}, Deadlocks: []string{}}
+	for _, d := range NewGraph(infos).Deadlocks() {
+		p.Deadlocks = append(p.Deadlocks, d.String())
+	}
+	for i := range infos {
+		info := &infos[i]
+		if !all && info.State != future.FuturePending {
+			continue
+		}
+		p.Entries = append(p.Entries, Entry{
+			ID:      info.ID,
+			Name:    info.Name,
+			State:   info.State.String(),
+			Created: info.Created,
+			Age:     info.Age,
+			Waiters: info.Waiters,
+			Stack:   info.Stack,
+
+			Waiting:   info.Waiting,
+			Producer:  info.Producer,
+			DependsOn: info.DependsOn,
+		})
+	}
+	return p
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_ = Dump(w)
+		return
+	}
+	p := Snapshot(q.Has("all"))
+	if q.Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(p) //nolint:errchkjson // the response cannot be fixed once writing it failed
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = page.Execute(w, p)
+}
+
+var page = template.Must(template.New("futures").Parse(pageTemplate)) //nolint:gochecknoglobals // parsed once
+
+const pageTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<title>futures</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { margin: 0; }
+</style>
+</head>
+<body>
+<h1>{{if .All}}All futures{{else}}Pending futures{{end}}</h1>
+{{if not .Enabled}}<p>The registry is disabled, see future.EnableRegistry.</p>
+{{else}}{{range .Deadlocks}}<p style="color: red">Deadlock: {{.}}</p>
+{{end}}<p>{{len .Entries}} futures. {{if .All}}<a href="?">Show pending only</a>{{else}}<a href="?all">Show all</a>{{end}} | <a href="?format=json{{if .All}}&amp;all{{end}}">JSON</a> | <a href="?format=dot">Wait-for graph</a></p>
+<table>
+<tr><th>ID</th><th>Name</th><th>State</th><th>Age</th><th>Waiters</th><th>Waiting goroutines</th><th>Producer</th><th>Depends on</th><th>Created at</th></tr>
+{{range .Entries}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.State}}</td><td>{{.Age}}</td><td>{{.Waiters}}</td><td>{{range .Waiting}}{{.}} {{end}}</td><td>{{with .Producer}}{{.}}{{end}}</td><td>{{range .DependsOn}}{{.}} {{end}}</td><td><pre>{{.Stack}}</pre></td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`
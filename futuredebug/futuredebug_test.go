@@ -0,0 +1,73 @@
+package futuredebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuredebug"
+)
+
+func get(tb testing.TB, target string, header http.Header) *httptest.ResponseRecorder {
+	tb.Helper()
+	r := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	futuredebug.Handler().ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		tb.Fatalf("GET %s responded with status %d, expected 200", target, w.Code)
+	}
+	return w
+}
+
+func decode(tb testing.TB, w *httptest.ResponseRecorder) futuredebug.Page {
+	tb.Helper()
+	p := futuredebug.Page{}
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		tb.Fatalf("decoding JSON response: %v", err)
+	}
+	return p
+}
+
+func TestHandlerDisabled(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	if p := decode(t, get(t, "/?format=json", nil)); p.Enabled || len(p.Entries) != 0 {
+		t.Errorf("disabled registry rendered as %+v, expected disabled and empty", p)
+	}
+	if body := get(t, "/", nil).Body.String(); !strings.Contains(body, "registry is disabled") {
+		t.Errorf("disabled registry rendered without a notice:\n%s", body)
+	}
+}
+
+func TestHandler(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	pending := future.New[int](future.WithName("<pending>"))
+	resolved := future.New[int](future.WithName("resolved"))
+	resolved.Resolve(1)
+
+	p := decode(t, get(t, "/", http.Header{"Accept": {"application/json"}}))
+	if !p.Enabled || p.All || len(p.Entries) != 1 || p.Entries[0].Name != "<pending>" || p.Entries[0].State != "pending" {
+		t.Errorf("pending futures rendered as %+v, expected only the pending future", p)
+	}
+	if p := decode(t, get(t, "/?format=json&all", nil)); !p.All || len(p.Entries) != 2 || p.Entries[1].State != "resolved" {
+		t.Errorf("all futures rendered as %+v, expected both futures", p)
+	}
+
+	w := get(t, "/", nil)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("listing rendered with content type %q, expected HTML", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "&lt;pending&gt;") || !strings.Contains(body, "TestHandler") || strings.Contains(body, ">resolved<") {
+		t.Errorf("pending futures rendered as HTML without the escaped name and creation stack of the pending future only:\n%s", body)
+	}
+	if w := get(t, "/?format=dot", nil); !strings.HasPrefix(w.Header().Get("Content-Type"), "text/vnd.graphviz") || !strings.Contains(w.Body.String(), `label="future`) {
+		t.Errorf("wait-for graph rendered as %q with content type %q, expected DOT", w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	pending.Resolve(1)
+}
@@ -0,0 +1,249 @@
+package futuredebug
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/daishe/go-future"
+)
+
+// Node is a node of the wait-for graph - either a goroutine or a future.
+type Node struct {
+	Goroutine uint64 // identifier of the goroutine, zero for futures
+	Future    uint64 // identifier of the future, zero for goroutines
+	Name      string // name of the future (see future.WithName)
+}
+
+// String returns a short description of the node, like `goroutine 7` or `future 3 "name"`.
+func (n Node) String() string {
+	if n.Future == 0 {
+		return "goroutine " + strconv.FormatUint(n.Goroutine, 10)
+	}
+	s := "future " + strconv.FormatUint(n.Future, 10)
+	if n.Name != "" {
+		s += " " + strconv.Quote(n.Name)
+	}
+	return s
+}
+
+func (n Node) dotID() string {
+	if n.Future == 0 {
+		return "g" + strconv.FormatUint(n.Goroutine, 10)
+	}
+	return "f" + strconv.FormatUint(n.Future, 10)
+}
+
+func compareNodes(a, b Node) int {
+	if (a.Future == 0) != (b.Future == 0) {
+		if a.Future == 0 {
+			return 1 // goroutines go after futures
+		}
+		return -1
+	}
+	return cmp.Or(cmp.Compare(a.Future, b.Future), cmp.Compare(a.Goroutine, b.Goroutine))
+}
+
+// Edge is an edge of the wait-for graph. The From node waits for the To node - a goroutine waits for a future it is blocked on, a future waits for the goroutine expected to resolve it (see future.Future.Claim), and a future waits for the futures it is resolved from.
+type Edge struct {
+	From, To Node
+}
+
+// Deadlock is a cycle in the wait-for graph. Every node waits for the next one, and the last one waits for the first one.
+type Deadlock struct {
+	Cycle []Node
+}
+
+// String returns the cycle, like `goroutine 7 -> future 3 "a" -> goroutine 9 -> future 4 "b" -> goroutine 7`.
+func (d Deadlock) String() string {
+	b := &strings.Builder{}
+	for _, n := range d.Cycle {
+		b.WriteString(n.String() + " -> ")
+	}
+	if len(d.Cycle) > 0 {
+		b.WriteString(d.Cycle[0].String())
+	}
+	return b.String()
+}
+
+// Graph is the wait-for graph among goroutines and pending futures.
+type Graph struct {
+	Nodes []Node // futures ordered by creation, followed by goroutines ordered by identifier
+	Edges []Edge
+}
+
+// NewGraph builds the wait-for graph of the given futures (see future.Registered). Only pending futures, and goroutines that wait for or are expected to resolve them, are part of the graph.
+func NewGraph(infos []future.FutureInfo) *Graph {
+	pending := map[uint64]Node{}
+	for i := range infos {
+		if info := &infos[i]; info.State == future.FuturePending {
+			pending[info.ID] = Node{Future: info.ID, Name: info.Name}
+		}
+	}
+	g := &Graph{}
+	goroutines := map[uint64]Node{}
+	goroutine := func(id uint64) Node {
+		n, ok := goroutines[id]
+		if !ok {
+			n = Node{Goroutine: id}
+			goroutines[id] = n
+		}
+		return n
+	}
+	for i := range infos {
+		info := &infos[i]
+		f, ok := pending[info.ID]
+		if !ok {
+			continue
+		}
+		g.Nodes = append(g.Nodes, f)
+		for _, id := range info.Waiting {
+			g.Edges = append(g.Edges, Edge{From: goroutine(id), To: f})
+		}
+		if info.Producer != 0 {
+			g.Edges = append(g.Edges, Edge{From: f, To: goroutine(info.Producer)})
+		}
+		for _, id := range info.DependsOn {
+			if dep, ok := pending[id]; ok {
+				g.Edges = append(g.Edges, Edge{From: f, To: dep})
+			}
+		}
+	}
+	for _, n := range goroutines {
+		g.Nodes = append(g.Nodes, n)
+	}
+	slices.SortFunc(g.Nodes, compareNodes)
+	return g
+}
+
+// Deadlocks returns cycles of the graph, one for every group of nodes that wait for each other.
+func (g *Graph) Deadlocks() []Deadlock {
+	out := map[Node][]Node{}
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+	var deadlocks []Deadlock
+	for _, component := range components(g.Nodes, out) {
+		if len(component) > 1 {
+			deadlocks = append(deadlocks, Deadlock{Cycle: cycle(component, out)})
+		}
+	}
+	return deadlocks
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz. Nodes and edges that are part of deadlocks are colored red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	nodesInCycle, edgesInCycle := map[Node]bool{}, map[Edge]bool{}
+	for _, d := range g.Deadlocks() {
+		for i, n := range d.Cycle {
+			nodesInCycle[n] = true
+			edgesInCycle[Edge{From: n, To: d.Cycle[(i+1)%len(d.Cycle)]}] = true
+		}
+	}
+	b := &strings.Builder{}
+	b.WriteString("digraph futures {\n")
+	for _, n := range g.Nodes {
+		shape := "box"
+		if n.Future == 0 {
+			shape = "ellipse"
+		}
+		b.WriteString("\t" + n.dotID() + " [label=" + strconv.Quote(n.String()) + ", shape=" + shape)
+		if nodesInCycle[n] {
+			b.WriteString(", color=red")
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		b.WriteString("\t" + e.From.dotID() + " -> " + e.To.dotID())
+		if edgesInCycle[e] {
+			b.WriteString(" [color=red]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Deadlocks returns cycles of the wait-for graph of futures in the registry (see future.EnableRegistry). The registry is not snapshotted atomically, so a cycle is a deadlock for sure only if it is reported repeatedly.
+func Deadlocks() []Deadlock {
+	return NewGraph(future.Registered()).Deadlocks()
+}
+
+// components returns strongly connected components of the graph, using Tarjan's algorithm.
+func components(nodes []Node, out map[Node][]Node) [][]Node {
+	index, low, onStack := map[Node]int{}, map[Node]int{}, map[Node]bool{}
+	var stack []Node
+	var result [][]Node
+	var visit func(n Node)
+	visit = func(n Node) {
+		index[n], low[n] = len(index), len(index)
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range out[n] {
+			if _, ok := index[m]; !ok {
+				visit(m)
+				low[n] = min(low[n], low[m])
+			} else if onStack[m] {
+				low[n] = min(low[n], index[m])
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		var component []Node
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			component = append(component, m)
+			if m == n {
+				break
+			}
+		}
+		slices.SortFunc(component, compareNodes)
+		result = append(result, component)
+	}
+	for _, n := range nodes {
+		if _, ok := index[n]; !ok {
+			visit(n)
+		}
+	}
+	slices.SortFunc(result, func(a, b []Node) int {
+		return compareNodes(a[0], b[0])
+	})
+	return result
+}
+
+// cycle returns the shortest cycle through the first node of the strongly connected component.
+func cycle(component []Node, out map[Node][]Node) []Node {
+	start := component[0]
+	within := map[Node]bool{}
+	for _, n := range component {
+		within[n] = true
+	}
+	prev := map[Node]Node{}
+	queue := []Node{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, m := range out[n] {
+			if m == start {
+				c := []Node{n}
+				for n != start {
+					n = prev[n]
+					c = append(c, n)
+				}
+				slices.Reverse(c)
+				return c
+			}
+			if _, seen := prev[m]; !seen && within[m] {
+				prev[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+	return component // unreachable for strongly connected components
+}
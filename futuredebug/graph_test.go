@@ -0,0 +1,103 @@
+package futuredebug_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuredebug"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	// goroutine 10 waits for future 1, resolved by goroutine 20, which waits for future 2, resolved from future 3, resolved by goroutine 10
+	// goroutine 30 waits for future 4, resolved by goroutine 20, outside of the cycle
+	// future 5 is resolved, so it is not part of the graph
+	infos := []future.FutureInfo{
+		{ID: 1, Name: "a", Waiting: []uint64{10}, Producer: 20},
+		{ID: 2, Name: "b", Waiting: []uint64{20}, DependsOn: []uint64{3, 5}},
+		{ID: 3, Producer: 10},
+		{ID: 4, Waiting: []uint64{30}, Producer: 20},
+		{ID: 5, State: future.FutureResolved, Waiting: []uint64{10}},
+	}
+	g := futuredebug.NewGraph(infos)
+	if len(g.Nodes) != 7 || len(g.Edges) != 7 {
+		t.Errorf("graph built with %d nodes and %d edges, expected 7 nodes and 7 edges", len(g.Nodes), len(g.Edges))
+	}
+
+	deadlocks := g.Deadlocks()
+	if len(deadlocks) != 1 {
+		t.Fatalf("%d deadlocks found, expected 1", len(deadlocks))
+	}
+	expected := `future 1 "a" -> goroutine 20 -> future 2 "b" -> future 3 -> goroutine 10 -> future 1 "a"`
+	if s := deadlocks[0].String(); s != expected {
+		t.Errorf("deadlock found as %s, expected %s", s, expected)
+	}
+
+	b := &strings.Builder{}
+	if err := g.WriteDOT(b); err != nil {
+		t.Fatalf("writing DOT: %v", err)
+	}
+	dot := b.String()
+	for _, line := range []string{
+		"digraph futures {",
+		"\tf1 [label=\"future 1 \\\"a\\\"\", shape=box, color=red];",
+		"\tf4 [label=\"future 4\", shape=box];",
+		"\tg30 [label=\"goroutine 30\", shape=ellipse];",
+		"\tg10 -> f1 [color=red];",
+		"\tf4 -> g20;",
+	} {
+		if !strings.Contains(dot, line+"\n") {
+			t.Errorf("graph written as DOT without line %q:\n%s", line, dot)
+		}
+	}
+}
+
+func TestGraphNoDeadlocks(t *testing.T) {
+	t.Parallel()
+
+	g := futuredebug.NewGraph([]future.FutureInfo{
+		{ID: 1, Waiting: []uint64{10, 11}, Producer: 20},
+		{ID: 2, Waiting: []uint64{20}, DependsOn: []uint64{3}},
+		{ID: 3},
+	})
+	if deadlocks := g.Deadlocks(); len(deadlocks) != 0 {
+		t.Errorf("deadlocks %v found in a graph without cycles", deadlocks)
+	}
+}
+
+func TestDeadlocks(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	a, b := future.New[int](future.WithName("a")), future.New[int](future.WithName("b"))
+	go func() {
+		a.Claim()
+		a.TryResolve(b.Get() + 1)
+	}()
+	go func() {
+		b.Claim()
+		b.Resolve(a.Get() + 1)
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	deadlocks := futuredebug.Deadlocks()
+	for ; len(deadlocks) == 0; deadlocks = futuredebug.Deadlocks() {
+		if time.Now().After(deadline) {
+			t.Fatalf("deadlock of two goroutines waiting for each other was not found")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(deadlocks) != 1 || len(deadlocks[0].Cycle) != 4 || !strings.Contains(deadlocks[0].String(), `"a"`) || !strings.Contains(deadlocks[0].String(), `"b"`) {
+		t.Errorf("deadlocks found as %v, expected a single cycle through both futures and goroutines", deadlocks)
+	}
+
+	b2 := &strings.Builder{}
+	if err := futuredebug.Dump(b2); err != nil || !strings.HasPrefix(b2.String(), "// deadlock: ") {
+		t.Errorf("dump written as (%q, %v), expected listing the deadlock first", b2.String(), err)
+	}
+	a.Resolve(0) // breaks the cycle
+	b.Wait()
+}
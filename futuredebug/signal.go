@@ -0,0 +1,47 @@
+package futuredebug
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/daishe/go-future"
+)
+
+// DumpOnSignal writes the wait-for graph of futures in the registry (see future.EnableRegistry) to w, in the DOT language of Graphviz (see Graph.WriteDOT), every time the process receives one of the given signals, or SIGQUIT if none are given. Deadlocks found in the graph are listed before it, as DOT comments.
+//
+// Note that catching SIGQUIT replaces its default behavior of exiting with a dump of all goroutines. The returned function stops catching the signals.
+func DumpOnSignal(w io.Writer, sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGQUIT}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = Dump(w)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// Dump writes the wait-for graph of futures in the registry (see future.EnableRegistry) to w, in the DOT language of Graphviz, preceded by a list of deadlocks found in it, as DOT comments.
+func Dump(w io.Writer) error {
+	g := NewGraph(future.Registered())
+	for _, d := range g.Deadlocks() {
+		if _, err := io.WriteString(w, "// deadlock: "+d.String()+"\n"); err != nil {
+			return err
+		}
+	}
+	return g.WriteDOT(w)
+}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package futuredebug_test
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future/futuredebug"
+)
+
+// syncBuffer is a builder safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestDumpOnSignal(t *testing.T) { //nolint:paralleltest // signals are process wide
+	b := &syncBuffer{}
+	stop := futuredebug.DumpOnSignal(b, syscall.SIGUSR1)
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("sending signal: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(b.String(), "digraph futures {") {
+		if time.Now().After(deadline) {
+			t.Fatalf("wait-for graph was not dumped on signal")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
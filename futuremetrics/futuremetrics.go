@@ -0,0 +1,213 @@
+// Package futuremetrics counts futures through their hooks (see future.Hooks) and samples the load of executors (see future.MeasuredExecutor), exposing both through expvar and as a handler serving the Prometheus text exposition format - for services that do not run OpenTelemetry (see the futureotel package for that). It has no dependencies beyond the standard library.
+package futuremetrics
+
+import (
+	"context"
+	"expvar"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/daishe/go-future"
+)
+
+// Names of metrics, as served by the handler.
+const (
+	MetricCreated   = "future_created_total"   // counter of created futures
+	MetricResolved  = "future_resolved_total"  // counter of futures resolved with a value
+	MetricRejected  = "future_rejected_total"  // counter of futures rejected with an error (other than cancellation)
+	MetricCancelled = "future_cancelled_total" // counter of cancelled futures (see future.Observer)
+	MetricPending   = "future_pending"         // gauge of pending futures, not counting ones garbage collected while pending
+
+	MetricExecutorWorkers     = "future_executor_workers"      // gauge of the maximum number of concurrently running tasks, by executor
+	MetricExecutorBusy        = "future_executor_busy_workers" // gauge of workers running tasks, by executor
+	MetricExecutorQueued      = "future_executor_queued_tasks" // gauge of tasks waiting to be started, by executor
+	MetricExecutorUtilization = "future_executor_utilization"  // gauge of the fraction of busy workers, by executor
+
+	LabelExecutor = "executor" // label holding the name of the executor
+)
+
+// Metrics counts futures it observes as hooks and samples the load of added executors. Register it with future.RegisterHooks to count all futures, or for a single executor with future.Hooked.
+type Metrics struct {
+	created, resolved, rejected, cancelled, pending atomic.Int64
+
+	mu        sync.Mutex
+	executors []*namedExecutor
+}
+
+type namedExecutor struct {
+	name string
+	ex   future.MeasuredExecutor
+}
+
+// Snapshot is a snapshot of metrics, as published through expvar.
+type Snapshot struct {
+	Created   int64              `json:"created"`
+	Resolved  int64              `json:"resolved"`
+	Rejected  int64              `json:"rejected"`
+	Cancelled int64              `json:"cancelled"`
+	Pending   int64              `json:"pending"`   // futures neither settled nor garbage collected
+	Executors []ExecutorSnapshot `json:"executors"` // added executors, ordered by name
+}
+
+// ExecutorSnapshot is a snapshot of the load of a single executor.
+type ExecutorSnapshot struct {
+	Name        string  `json:"name"`
+	Workers     int     `json:"workers"`
+	Busy        int     `json:"busy"`
+	Queued      int     `json:"queued"`
+	Utilization float64 `json:"utilization"`
+}
+
+var _ future.Hooks = (*Metrics)(nil)
+
+// New creates metrics with all counters at zero and no executors.
+func New() *Metrics {
+	return &Metrics{}
+}
+
+// Created counts the created future.
+func (m *Metrics) Created(context.Context, future.HookInfo) future.Observer {
+	m.created.Add(1)
+	m.pending.Add(1)
+	return observer{m}
+}
+
+// AddExecutor adds the executor, whose load is sampled under the given name each time metrics are read. Executors wrapped by future.Hooked do not report their load, so add the wrapped executor instead. The returned function removes the executor. Names identify series of executors, so AddExecutor panics if an executor with the same name was already added (and not removed).
+func (m *Metrics) AddExecutor(name string, ex future.MeasuredExecutor) (remove func()) {
+	e := &namedExecutor{name: name, ex: ex}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slices.ContainsFunc(m.executors, func(n *namedExecutor) bool { return n.name == name }) {
+		panic("futuremetrics: executor " + strconv.Quote(name) + " already added")
+	}
+	m.executors = append(m.executors, e)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.executors = slices.DeleteFunc(m.executors, func(n *namedExecutor) bool { return n == e })
+	}
+}
+
+// Snapshot returns current values of metrics.
+func (m *Metrics) Snapshot() Snapshot {
+	s := Snapshot{
+		Created:   m.created.Load(),
+		Resolved:  m.resolved.Load(),
+		Rejected:  m.rejected.Load(),
+		Cancelled: m.cancelled.Load(),
+		Pending:   m.pending.Load(),
+		Executors: []ExecutorSnapshot{},
+	}
+	m.mu.Lock()
+	executors := slices.Clone(m.executors)
+	m.mu.Unlock()
+	for _, e := range executors {
+		stats := e.ex.Stats()
+		s.Executors = append(s.Executors, ExecutorSnapshot{Name: e.name, Workers: stats.Workers, Busy: stats.Busy, Queued: stats.Queued, Utilization: stats.Utilization()})
+	}
+	slices.SortStableFunc(s.Executors, func(a, b ExecutorSnapshot) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return s
+}
+
+// Var returns a variable publishing snapshots of metrics (see Snapshot) through expvar, like:
+//
+//	expvar.Publish("futures", metrics.Var())
+func (m *Metrics) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return m.Snapshot()
+	})
+}
+
+// Handler returns a handler serving metrics in the Prometheus text exposition format, to be scraped by Prometheus or compatible collectors.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		s := m.Snapshot()
+		_, _ = io.WriteString(w, s.Prometheus())
+	})
+}
+
+// Prometheus returns the snapshot in the Prometheus text exposition format.
+func (s *Snapshot) Prometheus() string {
+	b := &strings.Builder{}
+	metric(b, MetricCreated, "counter", "Futures created.")
+	sample(b, MetricCreated, "", strconv.FormatInt(s.Created, 10))
+	metric(b, MetricResolved, "counter", "Futures resolved with a value.")
+	sample(b, MetricResolved, "", strconv.FormatInt(s.Resolved, 10))
+	metric(b, MetricRejected, "counter", "Futures rejected with an error, other than cancellation.")
+	sample(b, MetricRejected, "", strconv.FormatInt(s.Rejected, 10))
+	metric(b, MetricCancelled, "counter", "Futures cancelled.")
+	sample(b, MetricCancelled, "", strconv.FormatInt(s.Cancelled, 10))
+	metric(b, MetricPending, "gauge", "Futures pending.")
+	sample(b, MetricPending, "", strconv.FormatInt(s.Pending, 10))
+	if len(s.Executors) == 0 {
+		return b.String()
+	}
+
+	gauges := []struct {
+		name, help string
+		value      func(e *ExecutorSnapshot) string
+	}{
+		{MetricExecutorWorkers, "Maximum number of tasks the executor runs concurrently.", func(e *ExecutorSnapshot) string { return strconv.Itoa(e.Workers) }},
+		{MetricExecutorBusy, "Workers of the executor running tasks.", func(e *ExecutorSnapshot) string { return strconv.Itoa(e.Busy) }},
+		{MetricExecutorQueued, "Tasks submitted to the executor and not started yet.", func(e *ExecutorSnapshot) string { return strconv.Itoa(e.Queued) }},
+		{MetricExecutorUtilization, "Fraction of busy workers of the executor.", func(e *ExecutorSnapshot) string { return strconv.FormatFloat(e.Utilization, 'g', -1, 64) }},
+	}
+	for _, g := range gauges {
+		metric(b, g.name, "gauge", g.help)
+		for i := range s.Executors {
+			e := &s.Executors[i]
+			sample(b, g.name, LabelExecutor+`="`+escapeLabel(e.Name)+`"`, g.value(e))
+		}
+	}
+	return b.String()
+}
+
+type observer struct {
+	m *Metrics
+}
+
+func (o observer) Resolved(err error) {
+	if err != nil {
+		o.m.rejected.Add(1)
+	} else {
+		o.m.resolved.Add(1)
+	}
+	o.m.pending.Add(-1)
+}
+
+func (o observer) Cancelled(error) {
+	o.m.cancelled.Add(1)
+	o.m.pending.Add(-1)
+}
+
+func (o observer) Collected() {
+	o.m.pending.Add(-1)
+}
+
+func (observer) WaitStarted() func() { return nil }
+
+func metric(b *strings.Builder, name, typ, help string) {
+	b.WriteString("# HELP " + name + " " + help + "\n# TYPE " + name + " " + typ + "\n")
+}
+
+func sample(b *strings.Builder, name, labels, value string) {
+	b.WriteString(name)
+	if labels != "" {
+		b.WriteString("{" + labels + "}")
+	}
+	b.WriteString(" " + value + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`) //nolint:gochecknoglobals // built once
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
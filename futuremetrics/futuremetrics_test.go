@@ -0,0 +1,157 @@
+package futuremetrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuremetrics"
+)
+
+var errTest = errors.New("test")
+
+// fakeExecutor runs tasks inline, reporting fixed stats.
+type fakeExecutor struct {
+	future.Inline
+
+	stats future.ExecutorStats
+}
+
+func (e *fakeExecutor) Stats() future.ExecutorStats {
+	return e.stats
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	ex := future.Hooked(future.Inline{}, m)
+	f := future.New[int](future.WithExecutor(ex))
+	_ = future.Then(f, func(int) (int, error) { return 0, errTest }, future.WithExecutor(ex))
+	_ = future.Then(f, func(v int) (int, error) { return v, nil }, future.WithExecutor(ex))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelled := future.Join2(ctx, &future.Future[int]{}, &future.Future[int]{}, func(int, int) (int, error) { return 0, nil }, future.WithExecutor(ex))
+	pending := future.New[int](future.WithExecutor(ex))
+	f.Resolve(1)
+	cancelled.Wait()
+
+	s := m.Snapshot()
+	expected := futuremetrics.Snapshot{Created: 5, Resolved: 2, Rejected: 1, Cancelled: 1, Pending: 1, Executors: []futuremetrics.ExecutorSnapshot{}}
+	if s.Created != expected.Created || s.Resolved != expected.Resolved || s.Rejected != expected.Rejected || s.Cancelled != expected.Cancelled || s.Pending != expected.Pending || len(s.Executors) != 0 {
+		t.Errorf("snapshot is %+v, expected %+v", s, expected)
+	}
+	runtime.KeepAlive(pending)
+}
+
+func TestMetricsCollected(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	_ = future.New[int](future.WithExecutor(future.Hooked(future.Inline{}, m)))
+	deadline := time.After(5 * time.Second)
+	for m.Snapshot().Pending != 0 {
+		runtime.GC()
+		select {
+		case <-time.After(time.Millisecond):
+		case <-deadline:
+			t.Fatalf("future collected while pending is still counted as pending")
+		}
+	}
+}
+
+func TestMetricsExecutors(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	_ = m.AddExecutor("b", &fakeExecutor{stats: future.ExecutorStats{Workers: 4, Busy: 1, Queued: 7}})
+	remove := m.AddExecutor("c", &fakeExecutor{})
+	_ = m.AddExecutor(`a "x"`, &fakeExecutor{stats: future.ExecutorStats{Workers: 2, Busy: 2}})
+	remove()
+
+	s := m.Snapshot()
+	expected := []futuremetrics.ExecutorSnapshot{
+		{Name: `a "x"`, Workers: 2, Busy: 2, Utilization: 1},
+		{Name: "b", Workers: 4, Busy: 1, Queued: 7, Utilization: 0.25},
+	}
+	if len(s.Executors) != len(expected) || s.Executors[0] != expected[0] || s.Executors[1] != expected[1] {
+		t.Errorf("executors are %+v, expected %+v", s.Executors, expected)
+	}
+}
+
+func TestMetricsExecutorsDuplicate(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	remove := m.AddExecutor("a", &fakeExecutor{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("executor added twice under the same name, expected a panic")
+			}
+		}()
+		m.AddExecutor("a", &fakeExecutor{})
+	}()
+	remove()
+	_ = m.AddExecutor("a", &fakeExecutor{stats: future.ExecutorStats{Workers: 1}})
+	if s := m.Snapshot(); len(s.Executors) != 1 || s.Executors[0].Workers != 1 {
+		t.Errorf("executors are %+v, expected the one added after removal", s.Executors)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	_ = m.AddExecutor(`a "x"`, &fakeExecutor{stats: future.ExecutorStats{Workers: 4, Busy: 1, Queued: 7}})
+	f := future.New[int](future.WithExecutor(future.Hooked(future.Inline{}, m)))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type is %q, expected Prometheus text format", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	for _, line := range []string{
+		"# TYPE future_created_total counter",
+		"future_created_total 1",
+		"future_resolved_total 0",
+		"# TYPE future_pending gauge",
+		"future_pending 1",
+		"# HELP future_executor_workers Maximum number of tasks the executor runs concurrently.",
+		`future_executor_workers{executor="a \"x\""} 4`,
+		`future_executor_busy_workers{executor="a \"x\""} 1`,
+		`future_executor_queued_tasks{executor="a \"x\""} 7`,
+		`future_executor_utilization{executor="a \"x\""} 0.25`,
+	} {
+		if !strings.Contains("\n"+string(body), "\n"+line+"\n") {
+			t.Errorf("served metrics do not contain line %q:\n%s", line, body)
+		}
+	}
+	runtime.KeepAlive(f)
+}
+
+func TestVar(t *testing.T) {
+	t.Parallel()
+
+	m := futuremetrics.New()
+	_ = m.AddExecutor("pool", future.NewPool(3))
+	f := future.New[int](future.WithExecutor(future.Hooked(future.Inline{}, m)))
+
+	s := futuremetrics.Snapshot{}
+	if err := json.Unmarshal([]byte(m.Var().String()), &s); err != nil {
+		t.Fatalf("published variable is not a snapshot: %v", err)
+	}
+	if s.Created != 1 || s.Pending != 1 || len(s.Executors) != 1 || s.Executors[0] != (futuremetrics.ExecutorSnapshot{Name: "pool", Workers: 3}) {
+		t.Errorf("published snapshot is %+v, expected a created pending future and an idle pool", s)
+	}
+	runtime.KeepAlive(f)
+}
@@ -0,0 +1,189 @@
+// Package futureotel adapts hooks of futures (see future.Hooks) to spans and metrics shaped like OpenTelemetry ones. The adapter depends only on small interfaces mirroring the OpenTelemetry tracing and metrics API, so that the module stays free of dependencies - implementing them on top of an OpenTelemetry tracer and meter takes a few lines of glue code.
+package futureotel
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+// Attribute is a key-value pair describing a span or a measurement. Values are strings or int64s.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a description of the attribute, like `future.name="x"`.
+func (a Attribute) String() string {
+	if s, ok := a.Value.(string); ok {
+		return a.Key + "=" + strconv.Quote(s)
+	}
+	if i, ok := a.Value.(int64); ok {
+		return a.Key + "=" + strconv.FormatInt(i, 10)
+	}
+	return a.Key + "=?"
+}
+
+// Code is the status of a span, mirroring codes of OpenTelemetry.
+type Code uint32
+
+// Span status codes.
+const (
+	Unset Code = iota
+	Error
+	Ok
+)
+
+// Tracer starts spans, like trace.Tracer of OpenTelemetry.
+type Tracer interface {
+	// Start starts a span, a child of the span in the context, if any.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single operation, like trace.Span of OpenTelemetry.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string)
+	RecordError(err error)
+	SetStatus(code Code, description string)
+	End()
+}
+
+// Meter creates instruments, like metric.Meter of OpenTelemetry.
+type Meter interface {
+	Float64Histogram(name, unit, description string) Float64Histogram
+	Int64UpDownCounter(name, unit, description string) Int64UpDownCounter
+}
+
+// Float64Histogram records a distribution of values, like metric.Float64Histogram of OpenTelemetry.
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Int64UpDownCounter records a value that goes up and down, like metric.Int64UpDownCounter of OpenTelemetry.
+type Int64UpDownCounter interface {
+	Add(ctx context.Context, delta int64, attrs ...Attribute)
+}
+
+// Names of attributes and instruments.
+const (
+	AttributeID      = "future.id"
+	AttributeName    = "future.name"
+	AttributeParent  = "future.parent_id"
+	AttributeOutcome = "future.outcome" // "resolved", "rejected", "cancelled" or "collected" (garbage collected while pending)
+
+	MetricDuration     = "future.duration"      // histogram of times between creation and settlement of futures, in seconds
+	MetricWaitDuration = "future.wait.duration" // histogram of times goroutines spent waiting for futures, in seconds
+	MetricPending      = "future.pending"       // number of pending futures
+)
+
+// Config configures the adapter.
+type Config struct {
+	Tracer Tracer       // tracer starting a span for every future, nil means no spans
+	Meter  Meter        // meter creating instruments, nil means no metrics
+	Clock  future.Clock // clock used to measure durations, nil means the system clock
+}
+
+// Hooks emits a span for every observed future, from its creation until it is settled, with events for waits, and metrics of durations and pending futures. Spans and measurements are described with the name of the future, metrics carry the outcome of futures too. Spans are children of the span in the context passed to hooks - the one given to the helper creating the future, or set with future.WithHookContext.
+type Hooks struct {
+	tracer       Tracer
+	clock        future.Clock
+	duration     Float64Histogram
+	waitDuration Float64Histogram
+	pending      Int64UpDownCounter
+}
+
+var _ future.Hooks = (*Hooks)(nil)
+
+// New creates hooks emitting spans and metrics. Register them with future.RegisterHooks, or for a single executor with future.Hooked.
+func New(c Config) *Hooks {
+	h := &Hooks{tracer: c.Tracer, clock: c.Clock}
+	if h.clock == nil {
+		h.clock = future.SystemClock{}
+	}
+	if c.Meter != nil {
+		h.duration = c.Meter.Float64Histogram(MetricDuration, "s", "Time between creation and settlement of futures.")
+		h.waitDuration = c.Meter.Float64Histogram(MetricWaitDuration, "s", "Time goroutines spent waiting for futures.")
+		h.pending = c.Meter.Int64UpDownCounter(MetricPending, "{future}", "Number of pending futures.")
+	}
+	return h
+}
+
+// Created starts the span of the future and counts it as pending.
+func (h *Hooks) Created(ctx context.Context, info future.HookInfo) future.Observer {
+	name := Attribute{Key: AttributeName, Value: info.Name}
+	var span Span
+	if h.tracer != nil {
+		spanName := "future"
+		if info.Name != "" {
+			spanName += " " + info.Name
+		}
+		ctx, span = h.tracer.Start(ctx, spanName)
+		attrs := []Attribute{{Key: AttributeID, Value: int64(info.ID)}, name} //nolint:gosec // identifiers do not overflow int64 in practice
+		if info.Parent != 0 {
+			attrs = append(attrs, Attribute{Key: AttributeParent, Value: int64(info.Parent)}) //nolint:gosec // identifiers do not overflow int64 in practice
+		}
+		span.SetAttributes(attrs...)
+	}
+	if h.pending != nil {
+		h.pending.Add(ctx, 1, name)
+	}
+	return &observer{hooks: h, ctx: ctx, span: span, created: h.clock.Now(), name: name}
+}
+
+type observer struct {
+	hooks   *Hooks
+	ctx     context.Context //nolint:containedctx // context of the span of the future, used for measurements
+	span    Span
+	created time.Time
+	name    Attribute
+}
+
+func (o *observer) Resolved(err error) {
+	if err != nil {
+		o.settle("rejected", err)
+		return
+	}
+	o.settle("resolved", nil)
+}
+
+func (o *observer) Cancelled(cause error) {
+	o.settle("cancelled", cause)
+}
+
+func (o *observer) Collected() {
+	o.settle("collected", nil)
+}
+
+func (o *observer) WaitStarted() func() {
+	start := o.hooks.clock.Now()
+	if o.span != nil {
+		o.span.AddEvent("wait started")
+	}
+	return func() {
+		if o.span != nil {
+			o.span.AddEvent("wait ended")
+		}
+		if o.hooks.waitDuration != nil {
+			o.hooks.waitDuration.Record(o.ctx, o.hooks.clock.Now().Sub(start).Seconds(), o.name)
+		}
+	}
+}
+
+func (o *observer) settle(outcome string, err error) {
+	attr := Attribute{Key: AttributeOutcome, Value: outcome}
+	if o.span != nil {
+		o.span.SetAttributes(attr)
+		if err != nil {
+			o.span.RecordError(err)
+			o.span.SetStatus(Error, err.Error())
+		}
+		o.span.End()
+	}
+	if o.hooks.duration != nil {
+		o.hooks.duration.Record(o.ctx, o.hooks.clock.Now().Sub(o.created).Seconds(), o.name, attr)
+		o.hooks.pending.Add(o.ctx, -1, o.name)
+	}
+}
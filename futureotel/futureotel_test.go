@@ -0,0 +1,222 @@
+package futureotel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+	"github.com/daishe/go-future/futureotel"
+)
+
+var errTest = errors.New("test")
+
+// recorder implements the tracer, the meter and their instruments, recording every call as a string.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+type span struct {
+	r    *recorder
+	name string
+}
+
+type instrument struct {
+	r    *recorder
+	name string
+}
+
+func (r *recorder) Start(ctx context.Context, name string) (context.Context, futureotel.Span) {
+	r.record("start " + name)
+	return ctx, &span{r: r, name: name}
+}
+
+func (r *recorder) Float64Histogram(name, _, _ string) futureotel.Float64Histogram {
+	return &instrument{r: r, name: name}
+}
+
+func (r *recorder) Int64UpDownCounter(name, _, _ string) futureotel.Int64UpDownCounter {
+	return &instrument{r: r, name: name}
+}
+
+func (r *recorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (s *span) SetAttributes(attrs ...futureotel.Attribute) {
+	s.r.record(fmt.Sprintf("%s attributes %v", s.name, attrs))
+}
+
+func (s *span) AddEvent(name string) {
+	s.r.record(s.name + " event " + name)
+}
+
+func (s *span) RecordError(err error) {
+	s.r.record(fmt.Sprintf("%s error %v", s.name, err))
+}
+
+func (s *span) SetStatus(code futureotel.Code, description string) {
+	s.r.record(fmt.Sprintf("%s status %d %s", s.name, code, description))
+}
+
+func (s *span) End() {
+	s.r.record(s.name + " end")
+}
+
+func (i *instrument) Record(_ context.Context, value float64, attrs ...futureotel.Attribute) {
+	i.r.record(fmt.Sprintf("%s %v %v", i.name, value, attrs))
+}
+
+func (i *instrument) Add(_ context.Context, delta int64, attrs ...futureotel.Attribute) {
+	i.r.record(fmt.Sprintf("%s %+d %v", i.name, delta, attrs))
+}
+
+var ids = regexp.MustCompile(`(future\.(?:parent_)?id)=\d+`)
+
+// idAttribute replaces identifiers of futures in recorded calls, which depend on other tests.
+func idAttribute(calls []string) []string {
+	for i, c := range calls {
+		calls[i] = ids.ReplaceAllString(c, "$1=ID")
+	}
+	return calls
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	clock := fakeclock.New(time.Time{})
+	ex := future.Hooked(future.Inline{}, futureotel.New(futureotel.Config{Tracer: r, Meter: r, Clock: clock}))
+	f := future.New[int](future.WithExecutor(ex), future.WithName("f"))
+	g := future.Then(f, func(int) (int, error) { return 0, errTest }, future.WithExecutor(ex), future.WithName("g"))
+	clock.Advance(time.Second)
+	f.Resolve(1)
+
+	expected := []string{
+		"start future f",
+		"future f attributes [future.id=ID future.name=\"f\"]",
+		"future.pending +1 [future.name=\"f\"]",
+		"start future g",
+		"future g attributes [future.id=ID future.name=\"g\" future.parent_id=ID]",
+		"future.pending +1 [future.name=\"g\"]",
+		"future f attributes [future.outcome=\"resolved\"]",
+		"future f end",
+		"future.duration 1 [future.name=\"f\" future.outcome=\"resolved\"]",
+		"future.pending -1 [future.name=\"f\"]",
+		"future g attributes [future.outcome=\"rejected\"]",
+		"future g error test",
+		"future g status 1 test",
+		"future g end",
+		"future.duration 1 [future.name=\"g\" future.outcome=\"rejected\"]",
+		"future.pending -1 [future.name=\"g\"]",
+	}
+	if calls := idAttribute(r.Calls()); !slices.Equal(calls, expected) {
+		t.Errorf("calls recorded as:\n%s\nexpected:\n%s", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+	}
+	if g.Err() == nil {
+		t.Errorf("continuation resolved, expected rejected")
+	}
+}
+
+// waitSignal is a hook sending to the channel whenever some goroutine starts waiting for an observed future.
+type waitSignal chan struct{}
+
+func (s waitSignal) Created(context.Context, future.HookInfo) future.Observer {
+	return s
+}
+
+func (s waitSignal) Resolved(error)  {}
+func (s waitSignal) Cancelled(error) {}
+func (s waitSignal) Collected()      {}
+func (s waitSignal) WaitStarted() func() {
+	s <- struct{}{}
+	return nil
+}
+
+func TestHooksWait(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	clock := fakeclock.New(time.Time{})
+	waiting := waitSignal(make(chan struct{}))
+	ex := future.Hooked(future.Inline{}, futureotel.New(futureotel.Config{Meter: r, Clock: clock}), waiting)
+	f := future.New[int](future.WithExecutor(ex))
+	waited := make(chan struct{})
+	go func() {
+		f.Wait()
+		close(waited)
+	}()
+	<-waiting
+	clock.Advance(2 * time.Second)
+	f.Resolve(1)
+	<-waited
+
+	if calls := r.Calls(); !slices.Contains(calls, "future.wait.duration 2 [future.name=\"\"]") {
+		t.Errorf("calls recorded as %q, expected wait of 2s", calls)
+	}
+}
+
+func TestHooksWaitOutOfOrder(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	clock := fakeclock.New(time.Time{})
+	waiting := waitSignal(make(chan struct{}))
+	ex := future.Hooked(future.Inline{}, futureotel.New(futureotel.Config{Meter: r, Clock: clock}), waiting)
+	f := future.New[int](future.WithExecutor(ex))
+	waited := make(chan struct{})
+	go func() {
+		f.Wait()
+		close(waited)
+	}()
+	<-waiting
+	clock.Advance(2 * time.Second)
+	ctx, cancel := context.WithCancel(t.Context())
+	awaited := make(chan struct{})
+	go func() {
+		future.Await(ctx, f)
+		close(awaited)
+	}()
+	<-waiting
+	clock.Advance(time.Second)
+	cancel()
+	<-awaited
+	clock.Advance(time.Second)
+	f.Resolve(1)
+	<-waited
+
+	calls := r.Calls()
+	for _, c := range []string{"future.wait.duration 1 [future.name=\"\"]", "future.wait.duration 4 [future.name=\"\"]"} {
+		if !slices.Contains(calls, c) {
+			t.Errorf("calls recorded as %q, expected %q", calls, c)
+		}
+	}
+}
+
+func TestHooksCancelled(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	ex := future.Hooked(future.NewScheduler(1), futureotel.New(futureotel.Config{Tracer: r}))
+	f := future.Go(func() int { return 1 }, future.WithExecutor(ex), future.WithDeadline(time.Unix(0, 0)))
+	f.Wait()
+	if calls := r.Calls(); !slices.Contains(calls, "future attributes [future.outcome=\"cancelled\"]") || !slices.Contains(calls, "future error "+future.ErrMissedDeadline.Error()) {
+		t.Errorf("calls recorded as %q, expected span of a cancelled future", calls)
+	}
+}
@@ -0,0 +1,87 @@
+// Package futureslog implements hooks of futures (see future.Hooks) logging their lifecycle with log/slog.
+package futureslog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+// Config configures the hooks.
+type Config struct {
+	Logger *slog.Logger // logger records are written to, nil means slog.Default
+	Level  slog.Level   // level of records about creation, resolution, rejection, cancellation and collection of futures, panics are always logged at slog.LevelError
+	Clock  future.Clock // clock used to measure latency, nil means the system clock
+}
+
+// Hooks log creation of every observed future, followed by one of its resolution, rejection, cancellation or garbage collection while pending, with the latency since creation and the error or cause, if any. Rejections with a *future.PanicError are logged as panics, at slog.LevelError, with the panic value and stack.
+//
+// All records are written with the context given to the hook (see future.Hooks) and carry the same "future" group of attributes - identifier ("id"), name ("name", if any) and identifier of the future it is resolved from ("parent_id", if any) - the same attributes futures are described with when logged themselves (see future.Future.LogValue).
+type Hooks struct {
+	logger *slog.Logger
+	level  slog.Level
+	clock  future.Clock
+}
+
+var _ future.Hooks = (*Hooks)(nil)
+
+// New creates hooks logging lifecycle of futures. Register them with future.RegisterHooks, or for a single executor with future.Hooked.
+func New(c Config) *Hooks {
+	h := &Hooks{logger: c.Logger, level: c.Level, clock: c.Clock}
+	if h.logger == nil {
+		h.logger = slog.Default()
+	}
+	if h.clock == nil {
+		h.clock = future.SystemClock{}
+	}
+	return h
+}
+
+// Created logs creation of the future.
+func (h *Hooks) Created(ctx context.Context, info future.HookInfo) future.Observer {
+	attrs := []any{slog.Uint64("id", info.ID)}
+	if info.Name != "" {
+		attrs = append(attrs, slog.String("name", info.Name))
+	}
+	if info.Parent != 0 {
+		attrs = append(attrs, slog.Uint64("parent_id", info.Parent))
+	}
+	o := &observer{hooks: h, ctx: ctx, created: h.clock.Now(), future: slog.Group("future", attrs...)}
+	h.logger.LogAttrs(ctx, h.level, "future created", o.future)
+	return o
+}
+
+type observer struct {
+	hooks   *Hooks
+	ctx     context.Context //nolint:containedctx // context records are written with
+	created time.Time
+	future  slog.Attr
+}
+
+func (o *observer) Resolved(err error) {
+	latency := slog.Duration("latency", o.hooks.clock.Now().Sub(o.created))
+	if pe := (*future.PanicError)(nil); errors.As(err, &pe) {
+		o.hooks.logger.LogAttrs(o.ctx, slog.LevelError, "future panicked", o.future, latency, slog.Any("panic", pe.Value), slog.String("stack", string(pe.Stack)))
+		return
+	}
+	if err != nil {
+		o.hooks.logger.LogAttrs(o.ctx, o.hooks.level, "future rejected", o.future, latency, slog.Any("error", err))
+		return
+	}
+	o.hooks.logger.LogAttrs(o.ctx, o.hooks.level, "future resolved", o.future, latency)
+}
+
+func (o *observer) Cancelled(cause error) {
+	latency := slog.Duration("latency", o.hooks.clock.Now().Sub(o.created))
+	o.hooks.logger.LogAttrs(o.ctx, o.hooks.level, "future cancelled", o.future, latency, slog.Any("cause", cause))
+}
+
+func (o *observer) Collected() {
+	latency := slog.Duration("latency", o.hooks.clock.Now().Sub(o.created))
+	o.hooks.logger.LogAttrs(o.ctx, o.hooks.level, "future collected", o.future, latency)
+}
+
+func (o *observer) WaitStarted() func() { return nil }
@@ -0,0 +1,92 @@
+package futureslog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+	"github.com/daishe/go-future/futureslog"
+)
+
+var errTest = errors.New("test")
+
+// syncBuffer is a buffer safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+var ids = regexp.MustCompile(`(future\.(?:parent_)?id)=\d+`)
+
+// Lines returns logged lines, with identifiers of futures (which depend on other tests) replaced.
+func (b *syncBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(ids.ReplaceAllString(b.b.String(), "$1=ID")), "\n")
+}
+
+func newLogger(b *syncBuffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey || a.Key == "stack" {
+			return slog.Attr{}
+		}
+		return a
+	}}))
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	b := &syncBuffer{}
+	clock := fakeclock.New(time.Time{})
+	ex := future.Hooked(future.Inline{}, futureslog.New(futureslog.Config{Logger: newLogger(b), Level: slog.LevelDebug, Clock: clock}))
+	f := future.New[int](future.WithExecutor(ex), future.WithName("f"))
+	g := future.Then(f, func(int) (int, error) { return 0, errTest }, future.WithExecutor(ex))
+	h := future.Then(f, func(int) (int, error) { panic("boom") }, future.WithExecutor(ex), future.WithName("h"))
+	clock.Advance(time.Second)
+	f.Resolve(1)
+	g.Wait()
+	h.Wait()
+
+	expected := []string{
+		`level=DEBUG msg="future created" future.id=ID future.name=f`,
+		`level=DEBUG msg="future created" future.id=ID future.parent_id=ID`,
+		`level=DEBUG msg="future created" future.id=ID future.name=h future.parent_id=ID`,
+		`level=DEBUG msg="future resolved" future.id=ID future.name=f latency=1s`,
+		`level=DEBUG msg="future rejected" future.id=ID future.parent_id=ID latency=1s error=test`,
+		`level=ERROR msg="future panicked" future.id=ID future.name=h future.parent_id=ID latency=1s panic=boom`,
+	}
+	if lines := b.Lines(); strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("logged:\n%s\nexpected:\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestHooksCancelled(t *testing.T) {
+	t.Parallel()
+
+	b := &syncBuffer{}
+	ex := future.Hooked(future.Inline{}, futureslog.New(futureslog.Config{Logger: newLogger(b)}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := future.Join2(ctx, &future.Future[int]{}, &future.Future[int]{}, func(int, int) (int, error) { return 0, nil }, future.WithExecutor(ex))
+	f.Wait()
+
+	lines := b.Lines()
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `level=INFO msg="future cancelled" future.id=ID latency=`) || !strings.HasSuffix(lines[1], ` cause="context canceled"`) {
+		t.Errorf("logged:\n%s\nexpected creation and cancellation of the future", strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,216 @@
+package futuretest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/daishe/go-future"
+)
+
+// Op is an operation performed on a Controlled future.
+type Op string
+
+// Operations recorded by Controlled futures.
+const (
+	OpWait    Op = "Wait"    // a goroutine started waiting for the future (in Wait, Get, Err, Result or future.Await)
+	OpResolve Op = "Resolve" // the future was resolved with a value
+	OpReject  Op = "Reject"  // the future was rejected with an error
+	OpCancel  Op = "Cancel"  // the future was cancelled
+)
+
+// Controlled is a future for unit tests. It embeds a real future, so that it can be handed to code under test wherever a *future.Future is expected (including Then, the Join and Zip functions), records operations performed on that future through its hooks (see future.Hooks) and tracks goroutines blocked waiting for it, so that a test can resolve, reject or cancel it at an exact point.
+//
+// Only waits that block are recorded - reading the result of a settled future, or receiving from its done channel, is not.
+type Controlled[T any] struct {
+	*future.Future[T] // the controlled future
+
+	ctl *controller
+	v   T
+	err error
+}
+
+// NewControlled creates a new, pending controlled future.
+func NewControlled[T any]() *Controlled[T] {
+	ctl := &controller{changed: make(chan struct{})}
+	c := &Controlled[T]{ctl: ctl}
+	// The task producing the result is held by the controller until the test settles the future, so that the future can be cancelled like a future whose task was dropped.
+	c.Future = future.Then(future.Resolved(struct{}{}), func(struct{}) (T, error) {
+		return c.v, c.err
+	}, future.WithExecutor(future.Hooked(ctl, ctl)))
+	return c
+}
+
+// Resolve resolves the future with the value. It panics if the future was already resolved.
+func (c *Controlled[T]) Resolve(v T) {
+	if !c.TryResolve(v) {
+		panic("futuretest: already resolved")
+	}
+}
+
+// TryResolve resolves the future with the value, unless it was already resolved. It reports whether the future was resolved by the call.
+func (c *Controlled[T]) TryResolve(v T) bool {
+	t := c.ctl.take()
+	if t == nil {
+		return false
+	}
+	c.v = v
+	t.Run()
+	return true
+}
+
+// Reject rejects the future with the error. It panics if the future was already resolved or if err is nil.
+func (c *Controlled[T]) Reject(err error) {
+	if !c.TryReject(err) {
+		panic("futuretest: already resolved")
+	}
+}
+
+// TryReject rejects the future with the error, unless it was already resolved. It reports whether the future was rejected by the call. It panics if err is nil.
+func (c *Controlled[T]) TryReject(err error) bool {
+	if err == nil {
+		panic("futuretest: rejected with nil error")
+	}
+	t := c.ctl.take()
+	if t == nil {
+		return false
+	}
+	c.err = err
+	t.Run()
+	return true
+}
+
+// Cancel rejects the future with context.Canceled, like an executor dropping the task of the future would - hooks observe it as cancelled. It panics if the future was already resolved.
+func (c *Controlled[T]) Cancel() {
+	t := c.ctl.take()
+	if t == nil {
+		panic("futuretest: already resolved")
+	}
+	t.Cancel(context.Canceled)
+}
+
+// Ops returns all recorded operations, in the order they were performed.
+func (c *Controlled[T]) Ops() []Op {
+	c.ctl.mu.Lock()
+	defer c.ctl.mu.Unlock()
+	return append([]Op(nil), c.ctl.ops...)
+}
+
+// Count returns the number of times the operation was recorded.
+func (c *Controlled[T]) Count(op Op) int {
+	c.ctl.mu.Lock()
+	defer c.ctl.mu.Unlock()
+	n := 0
+	for _, o := range c.ctl.ops {
+		if o == op {
+			n++
+		}
+	}
+	return n
+}
+
+// Blocked returns the number of goroutines that are currently blocked waiting for the future.
+func (c *Controlled[T]) Blocked() int {
+	c.ctl.mu.Lock()
+	defer c.ctl.mu.Unlock()
+	return c.ctl.blocked
+}
+
+// WaitBlocked blocks until exactly n goroutines are blocked waiting for the future. It allows tests to resolve the future only once the code under test reached the point of waiting for it.
+func (c *Controlled[T]) WaitBlocked(n int) {
+	for {
+		c.ctl.mu.Lock()
+		blocked, changed := c.ctl.blocked, c.ctl.changed
+		c.ctl.mu.Unlock()
+		if blocked == n {
+			return
+		}
+		<-changed
+	}
+}
+
+// BlockedMust fails the test, unless exactly n goroutines are currently blocked waiting for the future.
+func (c *Controlled[T]) BlockedMust(tb testing.TB, n int) {
+	tb.Helper()
+	if b := c.Blocked(); b != n {
+		tb.Errorf("%d goroutines blocked on future, expected %d", b, n)
+	}
+}
+
+// controller is both the executor holding the task of a controlled future and the hooks observing it.
+type controller struct {
+	mu      sync.Mutex
+	task    *future.Task  // task producing the result of the future, nil once taken
+	changed chan struct{} // closed and replaced whenever the number of blocked goroutines changes, not a sync.Cond so that WaitBlocked counts as durably blocked in synctest bubbles
+	ops     []Op
+	blocked int
+}
+
+var (
+	_ future.TaskExecutor = (*controller)(nil)
+	_ future.Hooks        = (*controller)(nil)
+	_ future.Observer     = (*controller)(nil)
+)
+
+func (c *controller) Go(task func()) {
+	c.Submit(future.Task{Run: task})
+}
+
+func (c *controller) Submit(t future.Task) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.task = &t
+}
+
+func (c *controller) Created(context.Context, future.HookInfo) future.Observer {
+	return c
+}
+
+func (c *controller) Resolved(err error) {
+	if err != nil {
+		c.record(OpReject)
+		return
+	}
+	c.record(OpResolve)
+}
+
+func (c *controller) Cancelled(error) {
+	c.record(OpCancel)
+}
+
+func (c *controller) Collected() {}
+
+func (c *controller) WaitStarted() func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = append(c.ops, OpWait)
+	c.blocked++
+	c.notify()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.blocked--
+		c.notify()
+	}
+}
+
+// take returns the task of the future, unless it was already taken.
+func (c *controller) take() *future.Task {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.task
+	c.task = nil
+	return t
+}
+
+func (c *controller) record(op Op) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = append(c.ops, op)
+}
+
+// notify wakes up goroutines blocked in WaitBlocked. It must be called with the mutex held.
+func (c *controller) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
@@ -0,0 +1,78 @@
+package futuretest_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+var errTest = errors.New("test")
+
+func TestControlled(t *testing.T) {
+	t.Parallel()
+
+	c := futuretest.NewControlled[int]()
+	got := future.Go(c.Get)
+	waited := future.Go(func() bool { c.Wait(); return true })
+
+	c.WaitBlocked(2)
+	c.BlockedMust(t, 2)
+	if futuretest.IsSuccessful(futuretest.GetResult(got, futuretest.IsDone)) {
+		t.Errorf("get returned before the controlled future was resolved")
+	}
+	c.Resolve(1)
+	if got.Get() != 1 || !waited.Get() {
+		t.Errorf("get returned %d after the controlled future was resolved with 1", got.Get())
+	}
+	c.WaitBlocked(0)
+
+	if v := c.Get(); v != 1 {
+		t.Errorf("get of resolved controlled future returned %d, expected 1", v)
+	}
+	c.BlockedMust(t, 0)
+	if c.TryResolve(2) || c.TryReject(errTest) {
+		t.Errorf("resolved controlled future settled again")
+	}
+	if ops, expected := c.Ops(), []futuretest.Op{futuretest.OpWait, futuretest.OpWait, futuretest.OpResolve}; !slices.Equal(ops, expected) {
+		t.Errorf("recorded operations %v, expected %v", ops, expected)
+	}
+}
+
+func TestControlledCancel(t *testing.T) {
+	t.Parallel()
+
+	c := futuretest.NewControlled[int]()
+	err := future.Go(c.Err)
+	c.WaitBlocked(1)
+	c.Cancel()
+	if !errors.Is(err.Get(), context.Canceled) {
+		t.Errorf("err of cancelled controlled future returned %v, expected %v", err.Get(), context.Canceled)
+	}
+	if ops, expected := c.Ops(), []futuretest.Op{futuretest.OpWait, futuretest.OpCancel}; !slices.Equal(ops, expected) {
+		t.Errorf("recorded operations %v, expected %v", ops, expected)
+	}
+}
+
+func TestControlledJoin(t *testing.T) {
+	t.Parallel()
+
+	a, b := futuretest.NewControlled[int](), futuretest.NewControlled[string]()
+	joined := future.Join2(t.Context(), a.Future, b.Future, func(int, string) (int, error) { return 0, nil }, future.WithExecutor(future.Inline{}))
+	waited := future.Go(func() bool { return future.Await(t.Context(), a) })
+	a.WaitBlocked(1)
+	b.Reject(errTest)
+	if err := joined.Err(); !errors.Is(err, errTest) {
+		t.Errorf("join rejected with %v, expected %v", err, errTest)
+	}
+	a.Resolve(1)
+	if !waited.Get() {
+		t.Errorf("await of controlled future was not successful")
+	}
+	if n := b.Count(futuretest.OpReject); n != 1 {
+		t.Errorf("reject recorded %d times, expected 1", n)
+	}
+}
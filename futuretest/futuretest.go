@@ -0,0 +1,314 @@
+// Package futuretest provides a harness for testing code built on futures - racing many goroutines against a single future and asserting on the combined outcome of all of them.
+//
+// A typical test creates a StartCond, sets up Results of operations to run concurrently once the condition is started, starts it and then asserts on the results:
+//
+//	start := futuretest.NewStartCond()
+//	resolvers := futuretest.NewResults(start, f, futuretest.TryResolve(1), futuretest.TryResolve(2))
+//	getters := futuretest.NewResults(start, f, futuretest.Get, futuretest.Get)
+//	start.Start()
+//
+//	futuretest.OneMust(t, futuretest.IsSuccessful, resolvers)
+//	futuretest.AllMust(t, futuretest.IsValueEqual(futuretest.FindOne(t, futuretest.IsSuccessful, resolvers).Value), getters)
+package futuretest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+// Result is the outcome of a single operation run on a future.
+type Result[T any] struct {
+	Value   T    // value returned by the operation
+	Success bool // whether the operation reported success
+	Panic   bool // whether the operation panicked
+}
+
+// GetResult runs fn on the future and returns its outcome, recovering from panics.
+func GetResult[T any](f *future.Future[T], fn func(*future.Future[T]) (T, bool)) (r *Result[T]) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r = &Result[T]{Panic: true}
+			return
+		}
+	}()
+	v, ok := fn(f)
+	r = &Result[T]{Value: v, Success: ok, Panic: false}
+	return
+}
+
+func (r *Result[T]) String() string {
+	return fmt.Sprintf("{Value:%v,Success:%v,Panic:%v}", r.Value, r.Success, r.Panic)
+}
+
+// StartCond is a condition that many goroutines can wait for, so that they are all released at once.
+type StartCond struct {
+	start chan struct{}
+}
+
+// NewStartCond creates a new, not yet started condition.
+func NewStartCond() StartCond {
+	return StartCond{start: make(chan struct{})}
+}
+
+// Start releases all goroutines waiting for the condition. It must be called at most once.
+func (sc StartCond) Start() {
+	close(sc.start)
+}
+
+// Wait blocks until the condition is started.
+func (sc StartCond) Wait() {
+	<-sc.start
+}
+
+// Results collects outcomes of operations run concurrently on a single future.
+type Results[T any] struct {
+	wg        *sync.WaitGroup
+	collected []*Result[T]
+}
+
+// NewResults runs every one of the operations on the future in its own goroutine, once the condition is started. Goroutines are already running when NewResults returns.
+func NewResults[T any](sc StartCond, f *future.Future[T], fns ...func(*future.Future[T]) (T, bool)) *Results[T] {
+	r := &Results[T]{
+		wg:        &sync.WaitGroup{},
+		collected: make([]*Result[T], len(fns)),
+	}
+
+	pre := NewStartCond()
+	defer pre.Start()
+
+	for i, fn := range fns {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			pre.Wait()
+			sc.Wait()
+			r.collected[i] = GetResult(f, fn)
+		}()
+	}
+
+	return r
+}
+
+// Range waits for all operations to complete and yields their outcomes, in the order of operations.
+func (r *Results[T]) Range(yield func(*Result[T]) bool) {
+	r.wg.Wait()
+	for _, x := range r.collected {
+		if !yield(x) {
+			return
+		}
+	}
+}
+
+func (r *Results[T]) String() string {
+	strs := []string{}
+	for x := range r.Range {
+		strs = append(strs, x.String())
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
+
+// Count returns the number of outcomes that pass the test and the total number of outcomes.
+func Count[T any](test func(*Result[T]) bool, results ...*Results[T]) (count, totalCount int) {
+	for _, res := range results {
+		for r := range res.Range {
+			if test(r) {
+				count++
+			}
+			totalCount++
+		}
+	}
+	return count, totalCount
+}
+
+// FindOne returns the only outcome that passes the test. It fails the test immediately, if there is no such outcome or more than one.
+func FindOne[T any](tb testing.TB, test func(*Result[T]) bool, results ...*Results[T]) *Result[T] {
+	tb.Helper()
+	c, _ := Count(test, results...)
+	if c == 0 {
+		tb.Fatalf("no result that passes test found: %v", results)
+		return nil
+	}
+	if c > 1 {
+		tb.Fatalf("more than one result that passes test found: %v", results)
+		return nil
+	}
+	for _, res := range results {
+		for r := range res.Range {
+			if test(r) {
+				return r
+			}
+		}
+	}
+	tb.Fatalf("count returned 1, but search found no matching results: %v", results)
+	return nil
+}
+
+// All reports whether all outcomes pass the test.
+func All[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
+	c, all := Count(test, results...)
+	return c == all
+}
+
+// AllMust fails the test, unless all outcomes pass the test.
+func AllMust[T any](tb testing.TB, test func(*Result[T]) bool, results ...*Results[T]) {
+	tb.Helper()
+	if !All(test, results...) {
+		tb.Errorf("all must pass: %v", results)
+	}
+}
+
+// AllExceptOne reports whether exactly one outcome does not pass the test.
+func AllExceptOne[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
+	c, all := Count(test, results...)
+	return all-c == 1
+}
+
+// AllExceptOneMust fails the test, unless exactly one outcome does not pass the test.
+func AllExceptOneMust[T any](tb testing.TB, test func(*Result[T]) bool, results ...*Results[T]) {
+	tb.Helper()
+	if !AllExceptOne(test, results...) {
+		tb.Errorf("all except one must pass: %v", results)
+	}
+}
+
+// One reports whether exactly one outcome passes the test.
+func One[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
+	c, _ := Count(test, results...)
+	return c == 1
+}
+
+// OneMust fails the test, unless exactly one outcome passes the test.
+func OneMust[T any](tb testing.TB, test func(*Result[T]) bool, results ...*Results[T]) {
+	tb.Helper()
+	if !One(test, results...) {
+		tb.Errorf("exactly one must pass: %v", results)
+	}
+}
+
+// None reports whether no outcome passes the test.
+func None[T any](test func(*Result[T]) bool, results ...*Results[T]) bool {
+	c, _ := Count(test, results...)
+	return c == 0
+}
+
+// NoneMust fails the test, unless no outcome passes the test.
+func NoneMust[T any](tb testing.TB, test func(*Result[T]) bool, results ...*Results[T]) {
+	tb.Helper()
+	if !None(test, results...) {
+		tb.Errorf("all must fail: %v", results)
+	}
+}
+
+// TryResolve returns an operation that attempts to resolve the future with the value. It succeeds if the future was resolved by it.
+func TryResolve[T any](v T) func(*future.Future[T]) (T, bool) {
+	return func(f *future.Future[T]) (T, bool) {
+		return v, f.TryResolve(v)
+	}
+}
+
+// Resolve returns an operation that resolves the future with the value, panicking if it was already resolved.
+func Resolve[T any](v T) func(*future.Future[T]) (T, bool) {
+	return func(f *future.Future[T]) (T, bool) {
+		f.Resolve(v)
+		return v, true
+	}
+}
+
+// TryReject returns an operation that attempts to reject the future with the error. It succeeds if the future was rejected by it.
+func TryReject[T any](err error) func(*future.Future[T]) (T, bool) {
+	return func(f *future.Future[T]) (T, bool) {
+		var z T
+		return z, f.TryReject(err)
+	}
+}
+
+// Reject returns an operation that rejects the future with the error, panicking if it was already resolved.
+func Reject[T any](err error) func(*future.Future[T]) (T, bool) {
+	return func(f *future.Future[T]) (T, bool) {
+		var z T
+		f.Reject(err)
+		return z, true
+	}
+}
+
+// ErrIs returns an operation that waits for the future and succeeds if it was rejected with an error matching the target (see errors.Is).
+func ErrIs[T any](target error) func(*future.Future[T]) (T, bool) {
+	return func(f *future.Future[T]) (T, bool) {
+		var z T
+		return z, errors.Is(f.Err(), target)
+	}
+}
+
+// IsDone is an operation that succeeds if the future is already resolved, without waiting for it.
+func IsDone[T any](f *future.Future[T]) (T, bool) {
+	var z T
+	select {
+	case <-f.Done():
+		return z, true
+	default:
+		return z, false
+	}
+}
+
+// Get is an operation that returns the value of the future, waiting for it.
+func Get[T any](f *future.Future[T]) (T, bool) {
+	return f.Get(), true
+}
+
+// WaitAndGet is an operation that waits for the future and then returns its value.
+func WaitAndGet[T any](f *future.Future[T]) (T, bool) {
+	f.Wait()
+	return f.Get(), true
+}
+
+// IsValueEqual returns a test that passes for outcomes that did not panic and returned the given value.
+func IsValueEqual[T comparable](to T) func(*Result[T]) bool {
+	return func(r *Result[T]) bool {
+		return !r.Panic && r.Value == to
+	}
+}
+
+// IsSuccessful is a test that passes for outcomes that did not panic and reported success.
+func IsSuccessful[T any](r *Result[T]) bool {
+	return !r.Panic && r.Success
+}
+
+// IsUnsuccessful is a test that passes for outcomes that did not panic and reported failure.
+func IsUnsuccessful[T any](r *Result[T]) bool {
+	return !r.Panic && !r.Success
+}
+
+// IsPanic is a test that passes for outcomes that panicked.
+func IsPanic[T any](r *Result[T]) bool {
+	return r.Panic
+}
+
+// Eventually fails the test, unless the awaitable (for example a future) is done within the duration.
+func Eventually(tb testing.TB, a future.Awaitable, d time.Duration) {
+	tb.Helper()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-a.Done():
+	case <-timer.C:
+		tb.Errorf("not done within %v", d)
+	}
+}
+
+// NeverResolves fails the test, if the awaitable (for example a future) is done within the duration. Note that it always blocks for the whole duration, unless the awaitable is done.
+func NeverResolves(tb testing.TB, a future.Awaitable, d time.Duration) {
+	tb.Helper()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-a.Done():
+		tb.Errorf("done within %v, expected it to never be done", d)
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,111 @@
+package futuretest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+// Recorder is a testing.TB that records failures instead of failing the test.
+type Recorder struct {
+	testing.TB
+
+	failed bool
+}
+
+func (r *Recorder) Helper() {}
+
+func (r *Recorder) Errorf(string, ...any) {
+	r.failed = true
+}
+
+func (r *Recorder) Fatalf(string, ...any) {
+	r.failed = true
+}
+
+func MustFail(t *testing.T, name string, fn func(tb testing.TB)) {
+	t.Helper()
+	r := &Recorder{TB: t}
+	fn(r)
+	if !r.failed {
+		t.Errorf("%s did not fail", name)
+	}
+}
+
+func MustPass(t *testing.T, name string, fn func(tb testing.TB)) {
+	t.Helper()
+	r := &Recorder{TB: t}
+	fn(r)
+	if r.failed {
+		t.Errorf("%s failed", name)
+	}
+}
+
+func TestNone(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	start := futuretest.NewStartCond()
+	isDone := futuretest.NewResults(start, f, futuretest.IsDone, futuretest.IsDone)
+	start.Start()
+
+	if !futuretest.None(futuretest.IsSuccessful, isDone) || futuretest.None(futuretest.IsUnsuccessful, isDone) {
+		t.Errorf("none returned unexpected result for %v", isDone)
+	}
+	MustPass(t, "none must of no passing results", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.NoneMust(tb, futuretest.IsSuccessful, isDone)
+	})
+	MustFail(t, "none must of passing results", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.NoneMust(tb, futuretest.IsUnsuccessful, isDone)
+	})
+}
+
+func TestFindOne(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	start := futuretest.NewStartCond()
+	resolvers := futuretest.NewResults(start, f, futuretest.TryResolve(1), futuretest.TryResolve(2), futuretest.TryResolve(3))
+	start.Start()
+
+	v := futuretest.FindOne(t, futuretest.IsSuccessful, resolvers).Value
+	if f.Get() != v {
+		t.Errorf("future resolved with %d, but successful resolver used %d", f.Get(), v)
+	}
+	MustFail(t, "find one of many passing results", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.FindOne(tb, futuretest.IsUnsuccessful, resolvers)
+	})
+}
+
+func TestEventually(t *testing.T) {
+	t.Parallel()
+
+	f := &future.Future[int]{}
+	go f.Resolve(1)
+	MustPass(t, "eventually of resolved future", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.Eventually(tb, f, time.Minute)
+	})
+	MustFail(t, "eventually of pending future", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.Eventually(tb, &future.Future[int]{}, time.Millisecond)
+	})
+}
+
+func TestNeverResolves(t *testing.T) {
+	t.Parallel()
+
+	MustPass(t, "never resolves of pending future", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.NeverResolves(tb, &future.Future[int]{}, time.Millisecond)
+	})
+	MustFail(t, "never resolves of resolved future", func(tb testing.TB) {
+		tb.Helper()
+		futuretest.NeverResolves(tb, future.Resolved(1), time.Minute)
+	})
+}
@@ -0,0 +1,230 @@
+//go:build go1.25
+
+package futuretest
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+const (
+	// idleTimeout is the time after which a bubble, whose goroutines are all blocked and in which nothing settles, is considered idle.
+	idleTimeout = 365 * 24 * time.Hour
+	// stackBufferSize is the size of the buffer holding stacks of goroutines creating futures.
+	stackBufferSize = 4096
+)
+
+// Scenario is a test run within a testing/synctest bubble by Settled. It observes every future created within the bubble and collects other awaitables that must be settled by the end of the scenario.
+type Scenario struct {
+	*testing.T // test of the bubble
+
+	bubble []byte // identifier of the bubble, as printed in stacks of its goroutines
+
+	mu      sync.Mutex
+	names   []string
+	tracked []future.Awaitable
+	futures []*scenarioFuture // futures created within the bubble, in order of creation
+	changed chan struct{}     // closed and replaced whenever one of the futures settles
+}
+
+// Track adds the awaitable to the set that must be settled once the bubble goes idle. The name is used to identify it in test failures. Futures created by future.New and by helpers of the future package within the bubble are checked without being tracked - Track is needed only for other awaitables, like zero value futures.
+func (s *Scenario) Track(name string, a future.Awaitable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+	s.tracked = append(s.tracked, a)
+}
+
+// Settled runs the scenario in a testing/synctest bubble, where time advances only once all goroutines of the bubble are blocked. After the scenario returns, Settled waits for the bubble to go idle and fails the test for every future created within the bubble by future.New or by helpers of the future package (it observes them through hooks, see future.RegisterHooks) that is still pending by then, or was garbage collected without being settled - such futures would keep their waiters parked forever. Awaitables registered with Track are checked too.
+//
+// The bubble goes idle once all of its goroutines are blocked (see synctest.Wait) and no future settles within a year of bubble time, which passes instantly unless timers keep firing - so futures settled by timers, like those of retries with long backoff, are waited for. Tickers that keep running after the scenario returns delay reporting of unsettled futures.
+//
+// Futures, executors and clocks used within the scenario must be created within it too (see testing/synctest for details).
+func Settled(t *testing.T, scenario func(s *Scenario)) {
+	t.Helper()
+	synctest.Test(t, func(t *testing.T) {
+		t.Helper()
+		s := &Scenario{T: t, bubble: bubbleOf(goroutineStack()), changed: make(chan struct{})}
+		defer future.RegisterHooks(scenarioHooks{s})()
+		scenario(s)
+		awaitIdle(s.pending)
+		s.report(t)
+	})
+}
+
+// pending returns a channel that is closed once something pending may have settled, or nil if nothing is pending.
+func (s *Scenario) pending() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := firstPending(s.tracked); d != nil {
+		return d
+	}
+	for _, f := range s.futures {
+		if f.state == futurePending {
+			return s.changed
+		}
+	}
+	return nil
+}
+
+func (s *Scenario) report(tb testing.TB) {
+	tb.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reportPending(tb, s.names, s.tracked)
+	for _, f := range s.futures {
+		name := ""
+		if f.info.Name != "" {
+			name = " " + f.info.Name
+		}
+		switch f.state {
+		case futurePending:
+			tb.Errorf("future %d%s not settled when the bubble went idle, created by:\n%s", f.info.ID, name, f.stack)
+		case futureCollected:
+			tb.Errorf("future %d%s garbage collected without being settled, created by:\n%s", f.info.ID, name, f.stack)
+		case futureSettled:
+		}
+	}
+}
+
+// SettledMust waits for the current testing/synctest bubble to go idle (just like Settled) and fails the test for every given awaitable that is not done by then - no other futures are checked. It must be called from within a bubble.
+func SettledMust(tb testing.TB, as ...future.Awaitable) {
+	tb.Helper()
+	awaitIdle(func() <-chan struct{} {
+		return firstPending(as)
+	})
+	reportPending(tb, nil, as)
+}
+
+// awaitIdle waits until pending returns nil or the bubble goes idle - all of its goroutines are blocked and the channel returned by pending is not closed within idleTimeout of bubble time. Timers of the bubble fire in the meantime, as time advances.
+func awaitIdle(pending func() <-chan struct{}) {
+	for {
+		synctest.Wait()
+		changed := pending()
+		if changed == nil {
+			return
+		}
+		timer := time.NewTimer(idleTimeout)
+		select {
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// firstPending returns the done channel of the first awaitable that is not done, or nil if all are.
+func firstPending(as []future.Awaitable) <-chan struct{} {
+	for _, a := range as {
+		d := a.Done()
+		select {
+		case <-d:
+		default:
+			return d
+		}
+	}
+	return nil
+}
+
+func reportPending(tb testing.TB, names []string, as []future.Awaitable) {
+	tb.Helper()
+	for i, a := range as {
+		select {
+		case <-a.Done():
+			continue
+		default:
+		}
+		if i < len(names) {
+			tb.Errorf("%s not settled when the bubble went idle", names[i])
+		} else {
+			tb.Errorf("awaitable %d not settled when the bubble went idle", i)
+		}
+	}
+}
+
+// goroutineStack returns the stack of the calling goroutine, truncated to stackBufferSize.
+func goroutineStack() []byte {
+	b := make([]byte, stackBufferSize)
+	return b[:runtime.Stack(b, false)]
+}
+
+// bubbleOf returns the identifier of the synctest bubble printed in the header of the goroutine stack, or nil if the goroutine is not in a bubble.
+func bubbleOf(stack []byte) []byte {
+	header, _, _ := bytes.Cut(stack, []byte("\n"))
+	_, bubble, ok := bytes.Cut(header, []byte("synctest bubble "))
+	if !ok {
+		return nil
+	}
+	if i := bytes.IndexAny(bubble, ",]"); i >= 0 {
+		bubble = bubble[:i]
+	}
+	return bubble
+}
+
+type futureState int
+
+const (
+	futurePending futureState = iota
+	futureSettled
+	futureCollected
+)
+
+// scenarioHooks observe futures created within the bubble of the scenario.
+type scenarioHooks struct {
+	s *Scenario
+}
+
+func (h scenarioHooks) Created(_ context.Context, info future.HookInfo) future.Observer {
+	stack := goroutineStack()
+	if bubble := bubbleOf(stack); bubble == nil || !bytes.Equal(bubble, h.s.bubble) {
+		return nil
+	}
+	f := &scenarioFuture{s: h.s, info: info, stack: stack}
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.futures = append(h.s.futures, f)
+	return f
+}
+
+// scenarioFuture is a future created within the bubble of a scenario.
+type scenarioFuture struct {
+	s     *Scenario
+	info  future.HookInfo
+	stack []byte
+	state futureState // guarded by the mutex of the scenario
+}
+
+func (f *scenarioFuture) Resolved(error) {
+	f.settle()
+}
+
+func (f *scenarioFuture) Cancelled(error) {
+	f.settle()
+}
+
+// Collected marks the future as collected. It is called outside of the bubble, so the channel of the scenario (associated with the bubble) is left untouched.
+func (f *scenarioFuture) Collected() {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	f.state = futureCollected
+}
+
+func (f *scenarioFuture) WaitStarted() func() {
+	return nil
+}
+
+func (f *scenarioFuture) settle() {
+	f.s.mu.Lock()
+	defer f.s.mu.Unlock()
+	f.state = futureSettled
+	close(f.s.changed)
+	f.s.changed = make(chan struct{})
+}
@@ -0,0 +1,90 @@
+//go:build go1.25
+
+package futuretest_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+func TestSettled(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		f := &future.Future[int]{}
+		t.Track("future", f)
+		go func() {
+			time.Sleep(time.Hour)
+			f.Resolve(1)
+		}()
+	})
+}
+
+func TestSettledMust(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		pending := &future.Future[int]{}
+		MustFail(t.T, "settled must of never resolved future", func(tb testing.TB) {
+			tb.Helper()
+			futuretest.SettledMust(tb, future.Resolved(1), pending)
+		})
+		go func() {
+			time.Sleep(time.Hour)
+			pending.Resolve(1)
+		}()
+		MustPass(t.T, "settled must of eventually resolved future", func(tb testing.TB) {
+			tb.Helper()
+			futuretest.SettledMust(tb, future.Resolved(1), pending)
+		})
+	})
+}
+
+// TestSettledLongBackoff checks that Settled waits for futures settled by timers longer than a day.
+func TestSettledLongBackoff(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		attempts := 0
+		policy := future.RetryPolicy{MaxAttempts: 4, InitialDelay: 30 * 24 * time.Hour}
+		future.Retry(t.Context(), policy, func(context.Context) (int, error) { // not tracked, checked through hooks
+			attempts++
+			if attempts < 4 {
+				return 0, errTest
+			}
+			return attempts, nil
+		})
+	})
+}
+
+// TestSettledFails runs itself in a subprocess, expecting Settled to fail for the future left pending.
+func TestSettledFails(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(settledFailsEnv) != "" {
+		futuretest.Settled(t, func(*futuretest.Scenario) {
+			_ = future.New[int](future.WithName("forgotten"))
+			settled := future.New[int]()
+			settled.Resolve(1)
+		})
+		return
+	}
+	cmd := exec.CommandContext(t.Context(), os.Args[0], "-test.run=^TestSettledFails$") //nolint:gosec // runs the test binary itself
+	cmd.Env = append(os.Environ(), settledFailsEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("scenario with a pending future passed, output:\n%s", out)
+	}
+	if !strings.Contains(string(out), "forgotten not settled when the bubble went idle") || strings.Count(string(out), "not settled") != 1 {
+		t.Errorf("scenario with a pending future failed with output:\n%s\nexpected only the pending future to be reported", out)
+	}
+}
+
+const settledFailsEnv = "FUTURETEST_SETTLED_FAILS"
@@ -0,0 +1,107 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrHedgeLost is the cause of cancellation of contexts of hedged attempts (see Hedge) that were still running when the hedged future was resolved.
+var ErrHedgeLost = errors.New("future: another hedged attempt finished first")
+
+// Hedge returns a future that is resolved with the result of the first successful of up to n concurrent calls of fn. The first call is started immediately and every next one is started once the previous one does not finish within the delay (measured using the configured clock, see WithClock) or as soon as it fails. Calls are run using the configured executor (by default in a new goroutine).
+//
+// Once the returned future is resolved, contexts of calls that are still running are cancelled with ErrHedgeLost. If all n calls fail, the returned future is rejected with all of their errors joined. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If a call panics, the returned future is rejected with a *PanicError. It panics if n is not positive.
+func Hedge[T any](ctx context.Context, delay time.Duration, n int, fn func(context.Context) (T, error), opts ...Option) *Future[T] {
+	if n < 1 {
+		panic("future: hedge attempts count must be positive")
+	}
+	o := newOptions(opts)
+	ctx, cancel := context.WithCancelCause(ctx)
+	h := &hedge[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		delay:  delay,
+		n:      n,
+		fn:     fn,
+		o:      o,
+		r:      newFuture[T](ctx, o, nil),
+	}
+	context.AfterFunc(ctx, func() {
+		h.r.tryCancel(context.Cause(ctx))
+	})
+	h.r.afterResolve(h.stop)
+	h.launch()
+	return h.r
+}
+
+type hedge[T any] struct {
+	ctx    context.Context //nolint:containedctx // the context is passed to every attempt
+	cancel context.CancelCauseFunc
+	delay  time.Duration
+	n      int
+	fn     func(context.Context) (T, error)
+	o      *options
+	r      *Future[T]
+
+	mu      sync.Mutex
+	started int
+	errs    []error
+	timer   Timer
+}
+
+// launch starts the next attempt, unless all of them were already started.
+func (h *hedge[T]) launch() {
+	h.mu.Lock()
+	if h.r.resolved() || h.started == h.n {
+		h.mu.Unlock()
+		return
+	}
+	h.started++
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	if h.started < h.n {
+		h.timer = h.o.clock.AfterFunc(h.delay, h.launch)
+	}
+	h.mu.Unlock()
+
+	h.o.submit(func() {
+		h.finish(call(func() (T, error) { return h.fn(h.ctx) }))
+	}, h.fail)
+}
+
+func (h *hedge[T]) finish(v T, err error) {
+	if err == nil {
+		h.r.TryResolve(v)
+		return
+	}
+	if pe := (*PanicError)(nil); errors.As(err, &pe) {
+		h.r.TryReject(err)
+		return
+	}
+	h.fail(err)
+}
+
+func (h *hedge[T]) fail(err error) {
+	h.mu.Lock()
+	h.errs = append(h.errs, err)
+	failed := len(h.errs) == h.n
+	h.mu.Unlock()
+	if failed {
+		h.r.TryReject(errors.Join(h.errs...))
+		return
+	}
+	h.launch()
+}
+
+// stop cancels the remaining work, once the hedged future is resolved.
+func (h *hedge[T]) stop() {
+	h.mu.Lock()
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	h.mu.Unlock()
+	h.cancel(ErrHedgeLost)
+}
@@ -0,0 +1,120 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+// Hedged returns a function that, on its i-th call, waits for the i-th of the given futures or for its context to be cancelled. Contexts of all calls are sent to the returned channel.
+func Hedged(fs []*future.Future[int]) (fn func(context.Context) (int, error), started <-chan context.Context) {
+	calls := atomic.Int64{}
+	ch := make(chan context.Context, len(fs))
+	fn = func(ctx context.Context) (int, error) {
+		f := fs[calls.Add(1)-1]
+		ch <- ctx
+		select {
+		case <-f.Done():
+			return f.Result()
+		case <-ctx.Done():
+			return 0, context.Cause(ctx)
+		}
+	}
+	return fn, ch
+}
+
+func TestHedge(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fs := future.NewBatch[int](3)
+	fn, started := Hedged(fs)
+	f := future.Hedge(t.Context(), time.Second, 3, fn, future.WithClock(clock))
+
+	first := <-started
+	clock.Advance(time.Second - time.Nanosecond)
+	if len(started) != 0 {
+		t.Errorf("hedge started the second attempt before the delay elapsed")
+	}
+	clock.Advance(time.Nanosecond)
+	second := <-started
+
+	fs[1].Resolve(2)
+	if v, err := f.Result(); v != 2 || err != nil {
+		t.Errorf("hedge returned (%d, %v), expected (2, nil)", v, err)
+	}
+	<-first.Done()
+	if cause := context.Cause(first); !errors.Is(cause, future.ErrHedgeLost) {
+		t.Errorf("losing attempt cancelled with %v, expected %v", cause, future.ErrHedgeLost)
+	}
+	<-second.Done()
+
+	clock.Advance(time.Hour)
+	if len(started) != 0 {
+		t.Errorf("hedge started another attempt after it was resolved")
+	}
+}
+
+func TestHedgeFailures(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	fn := func(context.Context) (int, error) {
+		calls++
+		return 0, errTest
+	}
+	f := future.Hedge(t.Context(), time.Hour, 3, fn, future.WithExecutor(future.Inline{}), future.WithClock(fakeclock.New(time.Time{})))
+	if calls != 3 {
+		t.Errorf("hedge made %d attempts, expected every failure to start the next one right away", calls)
+	}
+	if !errors.Is(f.Err(), errTest) {
+		t.Errorf("hedge rejected with %v, expected %v", f.Err(), errTest)
+	}
+}
+
+func TestHedgeFailureThenSuccess(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fs := future.NewBatch[int](2)
+	fn, started := Hedged(fs)
+	f := future.Hedge(t.Context(), time.Hour, 2, fn, future.WithClock(clock))
+
+	<-started
+	fs[0].Reject(errTest)
+	<-started
+	fs[1].Resolve(2)
+	if v, err := f.Result(); v != 2 || err != nil {
+		t.Errorf("hedge returned (%d, %v), expected (2, nil)", v, err)
+	}
+}
+
+func TestHedgeCancel(t *testing.T) {
+	t.Parallel()
+
+	fs := future.NewBatch[int](2)
+	fn, started := Hedged(fs)
+	ctx, cancel := context.WithCancelCause(t.Context())
+	f := future.Hedge(ctx, time.Hour, 2, fn, future.WithClock(fakeclock.New(time.Time{})))
+
+	attempt := <-started
+	cancel(errCancel)
+	if !errors.Is(f.Err(), errCancel) {
+		t.Errorf("cancelled hedge rejected with %v, expected %v", f.Err(), errCancel)
+	}
+	<-attempt.Done()
+}
+
+func TestHedgePanic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Hedge(t.Context(), time.Hour, 2, func(context.Context) (int, error) { panic("boom") })
+	if pe := (&future.PanicError{}); !errors.As(f.Err(), &pe) {
+		t.Errorf("panicked hedge rejected with %v, expected *PanicError", f.Err())
+	}
+}
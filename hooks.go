@@ -0,0 +1,103 @@
+package future
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hooks observe the lifecycle of futures. Implementations must be safe for concurrent use.
+type Hooks interface {
+	// Created is called in the goroutine creating a future, with the context passed with WithHookContext or WithTracing (in that order) or, if there is none, with the context given to the helper creating the future (like Retry or the Join functions), or context.Background. The returned observer, if not nil, is notified about the rest of the lifecycle of the future.
+	Created(ctx context.Context, info HookInfo) Observer
+}
+
+// Observer is notified about the lifecycle of a single future. Its methods are called synchronously, in goroutines that resolve or wait for the future, so they should return quickly. Calls for different waiters may be concurrent.
+type Observer interface {
+	// Resolved is called once the future is resolved, with the error it was rejected with, if any.
+	Resolved(err error)
+	// Cancelled is called instead of Resolved, once the future is rejected because its task was dropped by the executor or because the context given to the helper that created it was cancelled.
+	Cancelled(cause error)
+	// Collected is called instead of Resolved and Cancelled, once the future is garbage collected while still pending - nothing can resolve it anymore. It is called in a cleanup goroutine of the runtime (see runtime.AddCleanup).
+	Collected()
+	// WaitStarted is called when a goroutine starts waiting for the future, in Wait (or Get, Err, Result) or in Await. The returned function, if not nil, is called by the same goroutine once it stops waiting, so that concurrent waits can be told apart.
+	WaitStarted() (ended func())
+}
+
+// HookInfo describes a created future.
+type HookInfo struct {
+	ID      uint64    // identifier of the future, unique within the process
+	Name    string    // name of the future (see WithName)
+	Parent  uint64    // identifier of the future the future is resolved from, by Then, Catch, Fallback (the first of its futures) or the Join functions, zero if none (or if the parent is not observed)
+	Created time.Time // time the future was created at
+}
+
+var (
+	hooksMu     sync.Mutex              //nolint:gochecknoglobals // guards updates of globalHooks
+	globalHooks atomic.Pointer[[]Hooks] //nolint:gochecknoglobals // hooks are process wide
+)
+
+// RegisterHooks registers hooks observing all futures created by New and by helpers of this package (zero value futures cannot be observed) from now on. Registering hooks makes creation of futures more expensive. The returned function unregisters the hooks; futures created before that are still observed.
+func RegisterHooks(h Hooks) (unregister func()) {
+	e := &registeredHooks{h} // unique, so that the same hooks can be registered more than once
+	updateHooks(func(hooks []Hooks) []Hooks {
+		return append(hooks, e)
+	})
+	return func() {
+		updateHooks(func(hooks []Hooks) []Hooks {
+			return slices.DeleteFunc(hooks, func(h Hooks) bool { return h == e })
+		})
+	}
+}
+
+// registeredHooks wraps globally registered hooks, so that they are comparable.
+type registeredHooks struct {
+	Hooks
+}
+
+func updateHooks(update func([]Hooks) []Hooks) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	var hooks []Hooks
+	if p := globalHooks.Load(); p != nil {
+		hooks = slices.Clone(*p)
+	}
+	hooks = update(hooks)
+	if len(hooks) == 0 {
+		globalHooks.Store(nil)
+		return
+	}
+	globalHooks.Store(&hooks)
+}
+
+type hookedExecutor struct {
+	Executor
+	hooks []Hooks
+}
+
+// Hooked returns an executor that runs tasks using ex, and whose futures - futures created by New and by helpers of this package with the returned executor set by WithExecutor - are observed by the given hooks, in addition to the globally registered ones (see RegisterHooks).
+func Hooked(ex Executor, hooks ...Hooks) Executor {
+	if he, ok := ex.(*hookedExecutor); ok {
+		return &hookedExecutor{Executor: he.Executor, hooks: append(slices.Clone(he.hooks), hooks...)}
+	}
+	return &hookedExecutor{Executor: ex, hooks: slices.Clone(hooks)}
+}
+
+// WithHookContext sets the context passed to hooks when futures are created (see Hooks), like the context of the request the futures are created for, so that spans of futures emitted by hooks nest in its trace. Unlike WithTracing, it neither traces futures with runtime/trace nor changes pprof labels. It takes precedence over the context passed with WithTracing and the context given to the helper creating the future.
+func WithHookContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.hookCtx = ctx
+	}
+}
+
+// observe calls the hooks for the created future.
+func (m *meta) observe(ctx context.Context, hooks []Hooks) {
+	info := HookInfo{ID: m.id, Name: m.name, Parent: m.parent, Created: m.created}
+	for _, h := range hooks {
+		if obs := h.Created(ctx, info); obs != nil {
+			m.observers = append(m.observers, obs)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+// hookRecorder records events of observed futures as strings, like "created 3 name parent 2", "wait started 3" or "resolved 3 <nil>".
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+type recordingObserver struct {
+	r  *hookRecorder
+	id uint64
+}
+
+func (r *hookRecorder) Created(_ context.Context, info future.HookInfo) future.Observer {
+	r.record(fmt.Sprintf("created %d %s parent %d", info.ID, info.Name, info.Parent))
+	return &recordingObserver{r: r, id: info.ID}
+}
+
+func (r *hookRecorder) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+func (r *hookRecorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (o *recordingObserver) Resolved(err error) {
+	o.r.record(fmt.Sprintf("resolved %d %v", o.id, err))
+}
+
+func (o *recordingObserver) Cancelled(cause error) {
+	o.r.record(fmt.Sprintf("cancelled %d %v", o.id, cause))
+}
+
+func (o *recordingObserver) Collected() {
+	o.r.record(fmt.Sprintf("collected %d", o.id))
+}
+
+func (o *recordingObserver) WaitStarted() func() {
+	o.r.record(fmt.Sprintf("wait started %d", o.id))
+	return func() { o.r.record(fmt.Sprintf("wait ended %d", o.id)) }
+}
+
+// hookID returns the identifier of the future, which the first event recorded for it is about.
+func hookID(tb testing.TB, event string) uint64 {
+	tb.Helper()
+	var id uint64
+	if _, err := fmt.Sscanf(event, "created %d", &id); err != nil {
+		tb.Fatalf("event %q is not a creation", event)
+	}
+	return id
+}
+
+func TestRegisterHooks(t *testing.T) { //nolint:paralleltest // hooks are process wide
+	rec := &hookRecorder{}
+	unregister := future.RegisterHooks(rec)
+
+	f := future.New[int](future.WithName("f"))
+	g := future.Then(f, func(v int) (int, error) { return v, errTest }, future.WithName("g"))
+	events := rec.Events()
+	if len(events) != 2 {
+		t.Fatalf("events recorded as %q, expected creation of two futures", events)
+	}
+	fID, gID := hookID(t, events[0]), hookID(t, events[1])
+	f.Resolve(1)
+	g.Wait()
+	unregister()
+	future.New[int]().Resolve(1)
+
+	expected := []string{
+		fmt.Sprintf("created %d f parent 0", fID),
+		fmt.Sprintf("created %d g parent %d", gID, fID),
+		fmt.Sprintf("resolved %d <nil>", fID),
+		fmt.Sprintf("wait started %d", gID),
+		fmt.Sprintf("resolved %d %v", gID, errTest),
+		fmt.Sprintf("wait ended %d", gID),
+	}
+	if events := rec.Events(); !slices.Equal(events, expected) {
+		t.Errorf("events recorded as %q, expected %q", events, expected)
+	}
+}
+
+func TestHooksCancelled(t *testing.T) { //nolint:paralleltest // hooks are process wide
+	rec := &hookRecorder{}
+	defer future.RegisterHooks(rec)()
+
+	dropped := future.Go(func() int { return 1 }, future.WithExecutor(future.NewScheduler(1)), future.WithDeadline(time.Unix(0, 0)))
+	ctx, cancel := context.WithCancelCause(context.Background())
+	joined := future.Join2(ctx, &future.Future[int]{}, &future.Future[int]{}, func(int, int) (int, error) { return 0, nil })
+	cancel(errCancel)
+	if !errors.Is(dropped.Err(), future.ErrMissedDeadline) || !errors.Is(joined.Err(), errCancel) {
+		t.Fatalf("futures rejected with (%v, %v), expected (%v, %v)", dropped.Err(), joined.Err(), future.ErrMissedDeadline, errCancel)
+	}
+
+	cancelled := 0
+	for _, e := range rec.Events() {
+		var id uint64
+		var cause string
+		if _, err := fmt.Sscanf(e, "cancelled %d %s", &id, &cause); err == nil {
+			cancelled++
+		}
+	}
+	if cancelled != 2 {
+		t.Errorf("events recorded as %q, expected both futures cancelled", rec.Events())
+	}
+}
+
+func TestHookedChan(t *testing.T) {
+	t.Parallel()
+
+	rec := &hookRecorder{}
+	ch := make(chan int, 1)
+	f := future.FromChan(ch, future.WithExecutor(future.Hooked(future.NewPool(1), rec)), future.WithName("chan"))
+	ch <- 1
+	f.Wait()
+	events := rec.Events()
+	if len(events) == 0 {
+		t.Fatalf("no events recorded, expected creation of the future of FromChan")
+	}
+	id := hookID(t, events[0])
+	for _, e := range []string{fmt.Sprintf("created %d chan parent 0", id), fmt.Sprintf("resolved %d <nil>", id)} {
+		if !slices.Contains(events, e) {
+			t.Errorf("events recorded as %q, expected %q", events, e)
+		}
+	}
+}
+
+func TestHookedFallback(t *testing.T) {
+	t.Parallel()
+
+	rec := &hookRecorder{}
+	ex := future.Hooked(future.Inline{}, rec)
+	fallback := future.Fallback([]*future.Future[int]{future.Rejected[int](errTest), future.Resolved(1)}, future.WithExecutor(ex), future.WithName("fallback"))
+	fallback.Wait()
+
+	events := rec.Events()
+	if len(events) == 0 {
+		t.Fatalf("no events recorded, expected creation of the future of Fallback")
+	}
+	fb := hookID(t, events[0])
+	if expected := []string{fmt.Sprintf("created %d fallback parent 0", fb), fmt.Sprintf("resolved %d <nil>", fb)}; !slices.Equal(events, expected) {
+		t.Errorf("events recorded as %q, expected %q", events, expected)
+	}
+}
+
+func TestHookedZip(t *testing.T) {
+	t.Parallel()
+
+	rec := &hookRecorder{}
+	zip := future.Zip2(future.Resolved(1), future.Resolved("2"), future.WithExecutor(future.Hooked(future.Inline{}, rec)), future.WithName("zip"))
+	zip.Wait()
+
+	events := rec.Events()
+	if len(events) == 0 {
+		t.Fatalf("no events recorded, expected creation of the future of Zip2")
+	}
+	z := hookID(t, events[0])
+	if expected := []string{fmt.Sprintf("created %d zip parent 0", z), fmt.Sprintf("resolved %d <nil>", z)}; !slices.Equal(events, expected) {
+		t.Errorf("events recorded as %q, expected %q", events, expected)
+	}
+}
+
+func TestRegisterHooksChanAndFallback(t *testing.T) { //nolint:paralleltest // hooks are process wide
+	rec := &hookRecorder{}
+	unregister := future.RegisterHooks(rec)
+	ch := make(chan int, 1)
+	fromChan := future.FromChan(ch)
+	rejected := future.Rejected[int](errTest)
+	fallback := future.Fallback([]*future.Future[int]{rejected, fromChan})
+	unregister()
+	ch <- 1
+	fallback.Wait()
+
+	events := rec.Events()
+	if len(events) < 2 {
+		t.Fatalf("events recorded as %q, expected creation of futures of FromChan and Fallback", events)
+	}
+	ch1, fb := hookID(t, events[0]), hookID(t, events[1])
+	for _, e := range []string{
+		fmt.Sprintf("created %d  parent 0", ch1),
+		fmt.Sprintf("created %d  parent 0", fb),
+		fmt.Sprintf("resolved %d <nil>", ch1),
+		fmt.Sprintf("resolved %d <nil>", fb),
+	} {
+		if !slices.Contains(events, e) {
+			t.Errorf("events recorded as %q, expected %q", events, e)
+		}
+	}
+}
+
+func TestHooked(t *testing.T) {
+	t.Parallel()
+
+	rec := &hookRecorder{}
+	pool := future.Hooked(future.NewPool(1), rec)
+	observed := future.Go(func() int { return 1 }, future.WithExecutor(pool), future.WithName("observed"))
+	observed.Wait()
+	future.Go(func() int { return 1 }, future.WithExecutor(future.NewPool(1))).Wait()
+
+	events := rec.Events()
+	if len(events) == 0 || events[0] != fmt.Sprintf("created %d observed parent 0", hookID(t, events[0])) {
+		t.Fatalf("events recorded as %q, expected creation of the observed future first", events)
+	}
+	if !slices.Contains(events, fmt.Sprintf("resolved %d <nil>", hookID(t, events[0]))) || slices.ContainsFunc(events[1:], func(e string) bool { return e[:7] == "created" }) {
+		t.Errorf("events recorded as %q, expected events of the observed future only", events)
+	}
+}
+
+func TestHookedScheduler(t *testing.T) {
+	t.Parallel()
+
+	rec := &hookRecorder{}
+	scheduler := future.Hooked(future.Hooked(future.NewScheduler(1)), rec)
+	f := future.Go(func() int { return 1 }, future.WithExecutor(scheduler), future.WithDeadline(time.Unix(0, 0)))
+	if !errors.Is(f.Err(), future.ErrMissedDeadline) {
+		t.Errorf("future with missed deadline rejected with %v, expected %v", f.Err(), future.ErrMissedDeadline)
+	}
+	if events := rec.Events(); len(events) == 0 || !slices.Contains(events, fmt.Sprintf("cancelled %d %v", hookID(t, events[0]), future.ErrMissedDeadline)) {
+		t.Errorf("events recorded as %q, expected the future cancelled", events)
+	}
+}
+
+type hookContextKey struct{}
+
+// hookContexts records values of hookContextKey in contexts passed to hooks, in order of creation of futures.
+type hookContexts struct {
+	mu     sync.Mutex
+	values []any
+}
+
+func (h *hookContexts) Created(ctx context.Context, _ future.HookInfo) future.Observer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.values = append(h.values, ctx.Value(hookContextKey{}))
+	return nil
+}
+
+func TestWithHookContext(t *testing.T) {
+	t.Parallel()
+
+	hooks := &hookContexts{}
+	ex := future.WithExecutor(future.Hooked(future.Inline{}, hooks))
+	hookCtx := context.WithValue(t.Context(), hookContextKey{}, "hook")
+	traceCtx := context.WithValue(t.Context(), hookContextKey{}, "trace")
+	helperCtx := context.WithValue(t.Context(), hookContextKey{}, "helper")
+
+	f := future.Go(func() int { return 1 }, ex, future.WithHookContext(hookCtx))
+	future.Then(f, func(v int) (int, error) { return v, nil }, ex)
+	future.Go(func() int { return 1 }, ex, future.WithTracing(traceCtx), future.WithHookContext(hookCtx))
+	future.Retry(helperCtx, future.RetryPolicy{}, func(context.Context) (int, error) { return 1, nil }, ex)
+	future.Retry(helperCtx, future.RetryPolicy{}, func(context.Context) (int, error) { return 1, nil }, ex, future.WithHookContext(hookCtx))
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	if expected := []any{"hook", nil, "hook", "helper", "hook"}; !slices.Equal(hooks.values, expected) {
+		t.Errorf("hooks got contexts with values %v, expected %v", hooks.values, expected)
+	}
+}
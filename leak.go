@@ -0,0 +1,107 @@
+package future
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// LeakReason tells why a future was reported as leaked.
+type LeakReason int
+
+// Reasons of leak reports.
+const (
+	LeakPending   LeakReason = iota + 1 // the future stayed pending for longer than the threshold
+	LeakCollected                       // the future was garbage collected without being resolved, after someone started waiting for it
+)
+
+// String returns a short description of the reason.
+func (r LeakReason) String() string {
+	switch r {
+	case LeakPending:
+		return "pending for too long"
+	case LeakCollected:
+		return "collected while awaited"
+	default:
+		return "LeakReason(" + strconv.Itoa(int(r)) + ")"
+	}
+}
+
+// Leak describes a future reported by leak detection.
+type Leak struct {
+	Reason  LeakReason
+	ID      uint64        // identifier of the future, unique within the process
+	Name    string        // name of the future (see WithName)
+	Created time.Time     // time the future was created at
+	Age     time.Duration // time between the creation of the future and the report
+	Waiters int           // number of goroutines blocked in Wait (or Get, Err, Result) of the future at the time of the report
+	Stack   string        // stack of the goroutine that created the future
+}
+
+// String returns a multiline description of the leak, ending with the stack that created the future.
+func (l Leak) String() string {
+	name := l.Name
+	if name == "" {
+		name = "future " + strconv.FormatUint(l.ID, 10)
+	}
+	return name + " " + l.Reason.String() + " (age " + l.Age.String() + ", " + strconv.Itoa(l.Waiters) + " waiters), created at:\n" + l.Stack
+}
+
+// LeakDetection configures the leak detection debug mode (see EnableLeakDetection).
+type LeakDetection struct {
+	Threshold time.Duration // age after which futures that are still pending are reported, zero means pending futures are never reported
+	Report    func(Leak)    // function called with every detected leak
+	Clock     Clock         // clock used to measure the age of futures, nil means the system clock
+}
+
+var leakDetection atomic.Pointer[LeakDetection] //nolint:gochecknoglobals // debug mode is process wide
+
+// EnableLeakDetection turns on the leak detection debug mode. It is meant for tests and debugging sessions, as it makes creation of futures considerably more expensive.
+//
+// While it is enabled, futures created by New and by helpers of this package (zero value futures cannot be tracked) are watched for two kinds of leaks - futures that stay pending for longer than the threshold, and futures that are garbage collected without ever being resolved, even though something waited for them (using Wait, Get, Err, Result or Done). The latter is the typical result of a producer returning early, which leaves waiters parked forever. Every leak is reported once, along with the stack that created the future.
+//
+// The returned function turns the debug mode off; futures created before that are no longer reported. Enabling leak detection again replaces the previous configuration.
+func EnableLeakDetection(ld LeakDetection) (disable func()) {
+	if ld.Report == nil {
+		panic("future: leak detection without report function")
+	}
+	p := &ld
+	leakDetection.Store(p)
+	return func() {
+		leakDetection.CompareAndSwap(p, nil)
+	}
+}
+
+func (ld *LeakDetection) clock() Clock {
+	if ld.Clock == nil {
+		return SystemClock{}
+	}
+	return ld.Clock
+}
+
+// watch starts the timer that reports the future, if it stays pending for longer than the threshold.
+func (m *meta) watch() {
+	if m.leaks == nil || m.leaks.Threshold <= 0 {
+		return
+	}
+	m.leakTimer = m.leaks.clock().AfterFunc(m.leaks.Threshold, func() {
+		if m.pending() {
+			m.reportLeak(LeakPending)
+		}
+	})
+}
+
+func (m *meta) reportLeak(reason LeakReason) {
+	if leakDetection.Load() != m.leaks {
+		return // leak detection was disabled or reconfigured in the meantime
+	}
+	m.leaks.Report(Leak{
+		Reason:  reason,
+		ID:      m.id,
+		Name:    m.name,
+		Created: m.created,
+		Age:     m.leaks.clock().Now().Sub(m.created),
+		Waiters: int(m.waiters.Load()),
+		Stack:   m.stack(),
+	})
+}
@@ -0,0 +1,180 @@
+package future_test
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+// leakRecorder collects leak reports of futures with the given name.
+type leakRecorder struct {
+	name   string
+	mu     sync.Mutex
+	leaks  []future.Leak
+	report chan struct{}
+}
+
+func newLeakRecorder(name string) *leakRecorder {
+	return &leakRecorder{name: name, report: make(chan struct{}, 1)}
+}
+
+func (r *leakRecorder) Report(l future.Leak) { //nolint:gocritic // signature of LeakDetection.Report
+	if l.Name != r.name {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leaks = append(r.leaks, l)
+	select {
+	case r.report <- struct{}{}:
+	default:
+	}
+}
+
+func (r *leakRecorder) Leaks() []future.Leak {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]future.Leak(nil), r.leaks...)
+}
+
+func TestLeakPending(t *testing.T) { //nolint:paralleltest // leak detection is process wide
+	clock := fakeclock.New(time.Time{})
+	rec := newLeakRecorder("pending")
+	disable := future.EnableLeakDetection(future.LeakDetection{Threshold: time.Minute, Report: rec.Report, Clock: clock})
+	defer disable()
+
+	leaked := future.New[int](future.WithName("pending"))
+	resolved := future.New[int](future.WithName("pending"))
+	clock.Advance(time.Minute - time.Nanosecond)
+	resolved.Resolve(1)
+	if leaks := rec.Leaks(); len(leaks) != 0 {
+		t.Fatalf("%d leaks reported before the threshold, expected none", len(leaks))
+	}
+
+	clock.Advance(time.Hour)
+	leaks := rec.Leaks()
+	if len(leaks) != 1 {
+		t.Fatalf("%d leaks reported, expected 1", len(leaks))
+	}
+	if l := leaks[0]; l.Reason != future.LeakPending || l.Age != time.Minute || l.Waiters != 0 {
+		t.Errorf("leak reported with (%v, %v, %d waiters), expected (%v, 1m0s, 0 waiters)", l.Reason, l.Age, l.Waiters, future.LeakPending)
+	}
+	if l := leaks[0]; !strings.Contains(l.Stack, "TestLeakPending") {
+		t.Errorf("leak reported with stack not containing the test function:\n%s", l.Stack)
+	}
+	leaked.Resolve(1)
+}
+
+func TestLeakPendingHelpers(t *testing.T) { //nolint:paralleltest // leak detection is process wide
+	clock := fakeclock.New(time.Time{})
+	rec := newLeakRecorder("helper")
+	disable := future.EnableLeakDetection(future.LeakDetection{Threshold: time.Minute, Report: rec.Report, Clock: clock})
+	defer disable()
+
+	source := &future.Future[int]{}
+	then := future.Then(source, func(v int) (int, error) { return v, nil }, future.WithName("helper"))
+	catch := future.Catch(source, func(error) (int, error) { return 0, nil }, future.WithName("helper"))
+	clock.Advance(time.Minute)
+	if leaks := rec.Leaks(); len(leaks) != 2 {
+		t.Errorf("%d leaks reported, expected 2", len(leaks))
+	}
+	source.Resolve(1)
+	then.Wait()
+	catch.Wait()
+}
+
+func TestLeakDisabled(t *testing.T) { //nolint:paralleltest // leak detection is process wide
+	clock := fakeclock.New(time.Time{})
+	rec := newLeakRecorder("disabled")
+	disable := future.EnableLeakDetection(future.LeakDetection{Threshold: time.Minute, Report: rec.Report, Clock: clock})
+
+	f := future.New[int](future.WithName("disabled"))
+	disable()
+	clock.Advance(time.Hour)
+	if leaks := rec.Leaks(); len(leaks) != 0 {
+		t.Errorf("%d leaks reported after disabling leak detection, expected none", len(leaks))
+	}
+	f.Resolve(1)
+}
+
+func TestLeakCollected(t *testing.T) { //nolint:paralleltest // leak detection is process wide
+	rec := newLeakRecorder("collected")
+	disable := future.EnableLeakDetection(future.LeakDetection{Report: rec.Report})
+	defer disable()
+
+	func() {
+		observed := future.New[int](future.WithName("collected"))
+		_ = observed.Done()
+		_ = future.New[int](future.WithName("collected")) // never awaited, so not reported
+		resolved := future.New[int](future.WithName("collected"))
+		_ = resolved.Done()
+		resolved.Resolve(1)
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for len(rec.Leaks()) == 0 {
+		runtime.GC()
+		select {
+		case <-rec.report:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("collected future was not reported")
+		}
+	}
+	runtime.GC()
+	time.Sleep(10 * time.Millisecond)
+	leaks := rec.Leaks()
+	if len(leaks) != 1 {
+		t.Fatalf("%d leaks reported, expected 1", len(leaks))
+	}
+	if l := leaks[0]; l.Reason != future.LeakCollected || !strings.Contains(l.Stack, "TestLeakCollected") {
+		t.Errorf("leak reported with reason %v and stack:\n%s\nexpected %v and stack containing the test function", l.Reason, l.Stack, future.LeakCollected)
+	}
+}
+
+func TestLeakCollectedWhileBlocked(t *testing.T) { //nolint:paralleltest // leak detection is process wide
+	accessors := map[string]func(f *future.Future[int]){
+		"Wait":   func(f *future.Future[int]) { f.Wait() },
+		"Get":    func(f *future.Future[int]) { _ = f.Get() },
+		"Err":    func(f *future.Future[int]) { _ = f.Err() },
+		"Result": func(f *future.Future[int]) { _, _ = f.Result() },
+	}
+	for name, access := range accessors { //nolint:paralleltest // leak detection is process wide
+		t.Run(name, func(t *testing.T) {
+			rec := newLeakRecorder("blocked in " + name)
+			disable := future.EnableLeakDetection(future.LeakDetection{Report: rec.Report})
+			defer disable()
+
+			go access(future.New[int](future.WithName("blocked in " + name))) // blocks forever, referencing nothing but the done channel
+
+			deadline := time.After(10 * time.Second)
+			for len(rec.Leaks()) == 0 {
+				runtime.GC()
+				select {
+				case <-rec.report:
+				case <-time.After(10 * time.Millisecond):
+				case <-deadline:
+					t.Fatalf("future collected while blocked in %s was not reported", name)
+				}
+			}
+			if l := rec.Leaks()[0]; l.Reason != future.LeakCollected || l.Waiters != 1 {
+				t.Errorf("leak reported with (%v, %d waiters), expected (%v, 1 waiter)", l.Reason, l.Waiters, future.LeakCollected)
+			}
+		})
+	}
+}
+
+func TestLeakString(t *testing.T) {
+	t.Parallel()
+
+	l := future.Leak{Reason: future.LeakCollected, ID: 7, Age: time.Second, Waiters: 2, Stack: "main.main(...)\n"}
+	expected := "future 7 collected while awaited (age 1s, 2 waiters), created at:\nmain.main(...)\n"
+	if s := l.String(); s != expected {
+		t.Errorf("leak formatted as %q, expected %q", s, expected)
+	}
+}
@@ -0,0 +1,78 @@
+package future
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures the behavior of helpers that create futures.
+type Option func(*options)
+
+type options struct {
+	executor Executor
+	priority int
+	deadline time.Time
+	repanic  bool
+	clock    Clock
+	name     string
+	traceCtx context.Context //nolint:containedctx // options are not retained past creation of futures
+	hookCtx  context.Context //nolint:containedctx // context passed to hooks (see WithHookContext)
+	hooks    []Hooks         // hooks of the executor (see Hooked)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		executor: Unbounded{},
+		clock:    SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithExecutor sets the executor used to run tasks. By default every task is run in a new goroutine.
+func WithExecutor(ex Executor) Option {
+	return func(o *options) {
+		if he, ok := ex.(*hookedExecutor); ok {
+			o.executor, o.hooks = he.Executor, he.hooks
+			return
+		}
+		if ex != nil {
+			o.executor, o.hooks = ex, nil
+		}
+	}
+}
+
+// WithPriority sets the priority of submitted tasks. Tasks with higher priority are started first by executors that order their queues (see Scheduler); other executors ignore it.
+func WithPriority(priority int) Option {
+	return func(o *options) {
+		o.priority = priority
+	}
+}
+
+// WithDeadline sets the time by which submitted tasks must be started. Tasks that were not started before their deadline are dropped and the futures they were supposed to resolve are rejected with ErrMissedDeadline.
+func WithDeadline(deadline time.Time) Option {
+	return func(o *options) {
+		o.deadline = deadline
+	}
+}
+
+func (o *options) submit(run func(), cancel func(error)) {
+	if te, ok := o.executor.(TaskExecutor); ok {
+		te.Submit(Task{Run: run, Cancel: cancel, Priority: o.priority, Deadline: o.deadline})
+		return
+	}
+	if o.deadline.IsZero() {
+		o.executor.Go(run)
+		return
+	}
+	deadline, clock := o.deadline, o.clock
+	o.executor.Go(func() {
+		if clock.Now().After(deadline) {
+			cancel(ErrMissedDeadline)
+			return
+		}
+		run()
+	})
+}
@@ -0,0 +1,52 @@
+package future
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error futures are rejected with, when the function that was supposed to produce their value (see Go, Then, Catch and JoinN) panics.
+//
+// By default, the panic is returned as an error by Err and Result, while Get returns the zero value. Get of futures created with WithRepanic option panics with the *PanicError instead, in every goroutine that calls it.
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack trace of the goroutine that panicked, captured at the time of the panic
+}
+
+// Error returns the panic value followed by the stack trace of the goroutine that panicked.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("future: panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value, if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// WithRepanic makes Get of created futures panic with the *PanicError they are rejected with, instead of returning the zero value - whether it was their own function that panicked, or the panic was propagated from a future they are resolved from. Err and Result still return the *PanicError as an error. The option affects only the created futures, not futures resolved from them.
+func WithRepanic() Option {
+	return func(o *options) {
+		o.repanic = true
+	}
+}
+
+// call returns the result of fn, or a *PanicError if fn panics.
+func call[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// repanic panics with the *PanicError in err, if there is one.
+func repanic(err error) {
+	if pe := (*PanicError)(nil); errors.As(err, &pe) {
+		panic(pe)
+	}
+}
@@ -0,0 +1,104 @@
+package future_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/daishe/go-future"
+)
+
+func panicker() int {
+	panic("boom")
+}
+
+func Recovered[T any](fn func() T) (r any) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+func TestGoPanic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(panicker)
+	if v := f.Get(); v != 0 {
+		t.Errorf("get of panicked future returned %d, expected zero value", v)
+	}
+	pe := &future.PanicError{}
+	if !errors.As(f.Err(), &pe) {
+		t.Fatalf("panicked future rejected with %v, expected *PanicError", f.Err())
+	}
+	if pe.Value != "boom" {
+		t.Errorf("panic error value is %v, expected %q", pe.Value, "boom")
+	}
+	if !strings.Contains(string(pe.Stack), "panicker") {
+		t.Errorf("panic error stack does not contain the panicking function:\n%s", pe.Stack)
+	}
+	if !strings.Contains(pe.Error(), "boom") {
+		t.Errorf("panic error message %q does not contain the panic value", pe.Error())
+	}
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(func() int { panic(errTest) })
+	if !errors.Is(f.Err(), errTest) {
+		t.Errorf("panic error does not unwrap to the panic value")
+	}
+}
+
+func TestRepanic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(panicker, future.WithRepanic())
+	for range 2 {
+		r := Recovered(f.Get)
+		if pe, ok := r.(*future.PanicError); !ok || pe.Value != "boom" {
+			t.Errorf("get of panicked future panicked with %v, expected *PanicError", r)
+		}
+	}
+	if _, err := f.Result(); err == nil {
+		t.Errorf("result of panicked future returned nil error")
+	}
+
+	rejected := future.Rejected[int](errTest)
+	if r := Recovered(rejected.Get); r != nil {
+		t.Errorf("get of rejected future panicked with %v", r)
+	}
+}
+
+func TestPanicPropagation(t *testing.T) {
+	t.Parallel()
+
+	f := future.Resolved(1)
+	then := future.Then(f, func(int) (int, error) { panic("boom") })
+	next := future.Then(then, func(v int) (int, error) { return v, nil })
+	if r := Recovered(next.Get); r != nil {
+		t.Errorf("get of future chained after panicked future panicked with %v, expected zero value", r)
+	}
+	repanicked := future.Then(then, func(v int) (int, error) { return v, nil }, future.WithRepanic())
+	if r := Recovered(repanicked.Get); r == nil {
+		t.Errorf("get of future created with repanic option and chained after panicked future did not panic")
+	}
+	direct := future.Then(f, func(int) (int, error) { panic("boom") }, future.WithRepanic())
+	if r := Recovered(future.Then(direct, func(v int) (int, error) { return v, nil }).Get); r != nil {
+		t.Errorf("get of future chained after panicked future created with repanic option panicked with %v, expected zero value", r)
+	}
+
+	recovered := future.Catch(next, func(err error) (int, error) {
+		if pe := (&future.PanicError{}); errors.As(err, &pe) {
+			return 2, nil
+		}
+		return 0, err
+	})
+	if v, err := recovered.Result(); v != 2 || err != nil {
+		t.Errorf("catch of panicked future returned (%d, %v), expected (2, nil)", v, err)
+	}
+
+	joined := future.Join2(t.Context(), f, recovered, func(int, int) (int, error) { panic("boom") })
+	if pe := (&future.PanicError{}); !errors.As(joined.Err(), &pe) {
+		t.Errorf("panicked join rejected with %v, expected *PanicError", joined.Err())
+	}
+}
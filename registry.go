@@ -0,0 +1,180 @@
+package future
+
+import (
+	"bytes"
+	"cmp"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FutureState is the state of a future, as seen by debugging facilities.
+type FutureState int32
+
+// States of futures.
+const (
+	FuturePending  FutureState = iota // the future is not resolved yet
+	FutureResolved                    // the future is resolved with a value
+	FutureRejected                    // the future is rejected with an error
+)
+
+// String returns the lowercase name of the state.
+func (s FutureState) String() string {
+	switch s {
+	case FuturePending:
+		return "pending"
+	case FutureResolved:
+		return "resolved"
+	case FutureRejected:
+		return "rejected"
+	default:
+		return "FutureState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// FutureInfo describes a future listed in the registry (see EnableRegistry).
+type FutureInfo struct {
+	ID      uint64 // identifier of the future, unique within the process
+	Name    string // name of the future (see WithName)
+	State   FutureState
+	Created time.Time     // time the future was created at
+	Age     time.Duration // time between the creation of the future and the snapshot
+	Waiters int           // number of goroutines blocked in Wait (or Get, Err, Result) of the future at the time of the snapshot
+	Stack   string        // stack of the goroutine that created the future
+
+	Waiting   []uint64 // identifiers of goroutines blocked waiting for the future, in Wait (or Get, Err, Result) or in Await
+	Producer  uint64   // identifier of the goroutine expected to resolve the future (see Claim), zero if unknown
+	DependsOn []uint64 // identifiers of futures the future is resolved from, by Then, Catch, Fallback or the Zip and Join functions
+}
+
+const goidBufferSize = 64
+
+type registry struct {
+	clock Clock
+
+	mu    sync.Mutex
+	metas map[uint64]*meta
+}
+
+var currentRegistry atomic.Pointer[registry] //nolint:gochecknoglobals // debug mode is process wide
+
+// EnableRegistry turns on the registry debug mode. It is meant for debugging sessions, as it makes creation of futures considerably more expensive. Out of the given options, only WithClock is used, to measure the age of futures.
+//
+// While it is enabled, futures created by New and by helpers of this package (zero value futures cannot be listed) are kept in a process wide registry until they are garbage collected, so that Registered can list what every stuck goroutine is waiting on. The futuredebug package serves the listing over HTTP.
+//
+// The returned function turns the debug mode off, dropping all listed futures. Enabling the registry again replaces the previous one.
+func EnableRegistry(opts ...Option) (disable func()) {
+	r := &registry{clock: newOptions(opts).clock, metas: map[uint64]*meta{}}
+	currentRegistry.Store(r)
+	return func() {
+		currentRegistry.CompareAndSwap(r, nil)
+	}
+}
+
+// Registered returns a snapshot of futures listed in the registry, ordered by creation. It returns nil if the registry is not enabled (see EnableRegistry).
+func Registered() []FutureInfo {
+	r := currentRegistry.Load()
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	metas := make([]*meta, 0, len(r.metas))
+	for _, m := range r.metas {
+		metas = append(metas, m)
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(metas, func(a, b *meta) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	now := r.clock.Now()
+	infos := make([]FutureInfo, 0, len(metas))
+	for _, m := range metas {
+		m.mu.Lock()
+		waiting, dependsOn := slices.Clone(m.waiting), slices.Clone(m.dependsOn)
+		m.mu.Unlock()
+		infos = append(infos, FutureInfo{
+			ID:      m.id,
+			Name:    m.name,
+			State:   FutureState(m.state.Load()),
+			Created: m.registered,
+			Age:     now.Sub(m.registered),
+			Waiters: int(m.waiters.Load()),
+			Stack:   m.stack(),
+
+			Waiting:   waiting,
+			Producer:  m.producer.Load(),
+			DependsOn: dependsOn,
+		})
+	}
+	return infos
+}
+
+func (r *registry) add(m *meta) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metas[m.id] = m
+}
+
+func (r *registry) remove(m *meta) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.metas, m.id)
+}
+
+// Claim marks the calling goroutine as the one expected to resolve the future, so that goroutines waiting for the future are known to wait for that goroutine (see futuredebug.Deadlocks). Futures created by Go, Then, Catch and the Join functions are claimed by the goroutine running their function. It does nothing unless the registry is enabled (see EnableRegistry).
+func (f *Future[T]) Claim() {
+	f.m.produce()
+}
+
+func (f *Future[T]) debugMeta() *meta {
+	return f.m
+}
+
+// tracked is implemented by futures, which record goroutines waiting for them in the registry.
+type tracked interface {
+	debugMeta() *meta
+}
+
+func noop() {}
+
+// waitFor records the calling goroutine as waiting for the awaitable, if it is a future, until the returned function is called.
+func waitFor(a Awaitable) (done func()) {
+	if t, ok := a.(tracked); ok {
+		if m := t.debugMeta(); m != nil {
+			return m.wait()
+		}
+	}
+	return noop
+}
+
+// produce records the calling goroutine as the one expected to resolve the future.
+func (m *meta) produce() {
+	if m != nil && m.registry != nil {
+		m.producer.Store(goid())
+	}
+}
+
+// dependOn records that the future is resolved from the result of the dep future.
+func (m *meta) dependOn(dep *meta) {
+	if m == nil || dep == nil || m.registry == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dependsOn = append(m.dependsOn, dep.id)
+}
+
+// goid returns the identifier of the calling goroutine, as printed in its stack.
+func goid() uint64 {
+	b := make([]byte, goidBufferSize)
+	b = bytes.TrimPrefix(b[:runtime.Stack(b, false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,160 @@
+package future_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+// registered returns futures in the registry with the given name.
+func registered(name string) []future.FutureInfo {
+	var infos []future.FutureInfo
+	all := future.Registered()
+	for i := range all {
+		if all[i].Name == name {
+			infos = append(infos, all[i])
+		}
+	}
+	return infos
+}
+
+func TestRegistry(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	clock := fakeclock.New(time.Time{})
+	disable := future.EnableRegistry(future.WithClock(clock))
+	defer disable()
+
+	pending := future.New[int](future.WithName("registry"))
+	clock.Advance(time.Second)
+	resolved := future.New[int](future.WithName("registry"))
+	rejected := future.Then(resolved, func(int) (int, error) { return 0, errTest }, future.WithName("registry"))
+	resolved.Resolve(1)
+	rejected.Wait()
+	clock.Advance(time.Second)
+
+	infos := registered("registry")
+	if len(infos) != 3 {
+		t.Fatalf("%d futures registered, expected 3", len(infos))
+	}
+	expected := []struct {
+		state future.FutureState
+		age   time.Duration
+	}{{future.FuturePending, 2 * time.Second}, {future.FutureResolved, time.Second}, {future.FutureRejected, time.Second}}
+	for i, e := range expected {
+		if infos[i].State != e.state || infos[i].Age != e.age {
+			t.Errorf("future %d registered as (%v, %v), expected (%v, %v)", i, infos[i].State, infos[i].Age, e.state, e.age)
+		}
+	}
+	if !strings.Contains(infos[0].Stack, "TestRegistry") {
+		t.Errorf("future registered with stack not containing the test function:\n%s", infos[0].Stack)
+	}
+	pending.Resolve(1)
+}
+
+func TestRegistryClock(t *testing.T) { //nolint:paralleltest // the registry and leak detection are process wide
+	clock, leakClock := fakeclock.New(time.Time{}), fakeclock.New(time.Unix(0, 0))
+	disable := future.EnableRegistry(future.WithClock(clock))
+	defer disable()
+	disableLeaks := future.EnableLeakDetection(future.LeakDetection{Report: func(future.Leak) {}, Clock: leakClock})
+	defer disableLeaks()
+
+	f := future.New[int](future.WithName("registry clock"))
+	clock.Advance(time.Second)
+	leakClock.Advance(time.Hour)
+	if infos := registered("registry clock"); len(infos) != 1 || !infos[0].Created.Equal(time.Time{}) || infos[0].Age != time.Second {
+		t.Errorf("futures registered as %+v, expected a future created at the time of the registry clock, 1s old", infos)
+	}
+	f.Resolve(1)
+}
+
+func TestRegistryWaiters(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	f := future.New[int](future.WithName("waiters"))
+	for range 2 {
+		go f.Wait()
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for infos := registered("waiters"); len(infos) != 1 || infos[0].Waiters != 2; infos = registered("waiters") {
+		if time.Now().After(deadline) {
+			t.Fatalf("futures registered as %+v, expected one future with 2 waiters", infos)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	f.Resolve(1)
+}
+
+func TestRegistryCollected(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	_ = future.New[int](future.WithName("collected"))
+	deadline := time.Now().Add(10 * time.Second)
+	for len(registered("collected")) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("collected future is still registered")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRegistryDisabled(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	f := future.New[int](future.WithName("disabled"))
+	disable()
+	if infos := future.Registered(); infos != nil {
+		t.Errorf("%d futures registered after disabling the registry, expected none", len(infos))
+	}
+	f.Resolve(1)
+}
+
+func TestFutureStateString(t *testing.T) {
+	t.Parallel()
+
+	for s, expected := range map[future.FutureState]string{future.FuturePending: "pending", future.FutureResolved: "resolved", future.FutureRejected: "rejected", 7: "FutureState(7)"} {
+		if s.String() != expected {
+			t.Errorf("state formatted as %q, expected %q", s.String(), expected)
+		}
+	}
+}
+
+func TestRegistryWaitFor(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	source := future.New[int](future.WithName("source"))
+	claimed := make(chan struct{})
+	go func() {
+		source.Claim()
+		close(claimed)
+	}()
+	<-claimed
+	then := future.Then(source, func(v int) (int, error) { return v, nil }, future.WithName("then"))
+	go func() { future.Await(context.Background(), then) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for infos := registered("then"); len(infos) != 1 || len(infos[0].Waiting) != 1; infos = registered("then") {
+		if time.Now().After(deadline) {
+			t.Fatalf("futures registered as %+v, expected a future with one waiting goroutine", infos)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s, th := registered("source")[0], registered("then")[0]
+	if s.Producer == 0 || len(s.Waiting) != 0 {
+		t.Errorf("claimed future registered with producer %d and waiting goroutines %v, expected a producer and none waiting", s.Producer, s.Waiting)
+	}
+	if len(th.DependsOn) != 1 || th.DependsOn[0] != s.ID || th.Producer != 0 {
+		t.Errorf("continuation registered as depending on %v and produced by %d, expected depending on %d and no producer yet", th.DependsOn, th.Producer, s.ID)
+	}
+	source.Resolve(1)
+	then.Wait()
+	if th := registered("then")[0]; th.Producer == 0 {
+		t.Errorf("resolved continuation registered without a producer")
+	}
+}
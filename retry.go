@@ -0,0 +1,183 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryDelay      = 100 * time.Millisecond
+	defaultRetryMultiplier = 2
+)
+
+// RetryPolicy describes when and how often Retry attempts to call its function again. The zero value retries every error forever, starting with 100ms delay that doubles after every attempt.
+type RetryPolicy struct {
+	MaxAttempts int           // maximum number of attempts, zero means no limit
+	MaxElapsed  time.Duration // maximum time since the first attempt after which no new attempt is started, zero means no limit
+
+	InitialDelay time.Duration // delay before the second attempt, zero means 100ms
+	MaxDelay     time.Duration // upper bound of the delay between attempts, zero means no bound
+	Multiplier   float64       // factor the delay is multiplied by after every attempt, zero means 2
+	Jitter       float64       // fraction (between 0 and 1) by which every delay is randomly lengthened or shortened, zero means no jitter
+
+	RetryIf func(error) bool // reports whether the attempt that failed with the given error should be retried, nil means every error is retried
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if pe := (*PanicError)(nil); errors.As(err, &pe) {
+		return false
+	}
+	return p.RetryIf == nil || p.RetryIf(err)
+}
+
+// delay returns the delay after the given (counted from 1) failed attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	initial, multiplier := p.InitialDelay, p.Multiplier
+	if initial <= 0 {
+		initial = defaultRetryDelay
+	}
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 {
+		d = min(d, float64(p.MaxDelay))
+	}
+	if p.Jitter > 0 {
+		d += d * min(p.Jitter, 1) * (2*rand.Float64() - 1) //nolint:gosec // jitter does not need a cryptographically secure source
+	}
+	return time.Duration(min(d, math.MaxInt64))
+}
+
+// RetryAttempt describes a single failed attempt of Retry.
+type RetryAttempt struct {
+	Start    time.Time     // time the attempt was started at
+	Duration time.Duration // time the attempt took
+	Err      error         // error the attempt failed with
+}
+
+// RetryError is the error futures created by Retry are rejected with. It holds the history of all failed attempts.
+type RetryError struct {
+	Attempts []RetryAttempt // failed attempts, in order
+	Err      error          // reason retrying stopped, either the error of the last attempt or the cause of context cancellation
+}
+
+// Error returns the number of failed attempts and the reason retrying stopped.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("future: retrying stopped after %d failed attempts: %v", len(e.Attempts), e.Err)
+}
+
+// Unwrap returns the reason retrying stopped.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry returns a future that is resolved with the result of the first successful call of fn. Failed calls are retried according to the policy, with delays measured using the configured clock (see WithClock), and every call is run using the configured executor (by default in a new goroutine). No goroutines are used for waiting between attempts.
+//
+// Once retrying stops, because the policy does not allow more attempts or because the context is cancelled, the returned future is rejected with a *RetryError. Attempts that panic are never retried.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(context.Context) (T, error), opts ...Option) *Future[T] {
+	o := newOptions(opts)
+	rt := &retry[T]{
+		ctx:    ctx,
+		policy: policy,
+		fn:     fn,
+		o:      o,
+		r:      newFuture[T](ctx, o, nil),
+	}
+	rt.start = rt.o.clock.Now()
+	stop := context.AfterFunc(ctx, func() { // registered once rt is initialized, as it may run right away
+		rt.fail(context.Cause(ctx))
+	})
+	rt.mu.Lock()
+	rt.stop = stop
+	rt.mu.Unlock()
+	rt.run()
+	return rt.r
+}
+
+type retry[T any] struct {
+	ctx    context.Context //nolint:containedctx // the context is passed to every attempt
+	policy RetryPolicy
+	fn     func(context.Context) (T, error)
+	o      *options
+	r      *Future[T]
+	start  time.Time
+
+	mu       sync.Mutex
+	stop     func() bool // stops watching the context, nil until the watch is registered
+	attempts []RetryAttempt
+	timer    Timer
+}
+
+func (rt *retry[T]) run() {
+	rt.o.submit(rt.attempt, rt.fail)
+}
+
+func (rt *retry[T]) attempt() {
+	if rt.r.resolved() {
+		return // context was cancelled in the meantime
+	}
+	if rt.ctx.Err() != nil {
+		rt.fail(context.Cause(rt.ctx)) // the context watch may not have run yet
+		return
+	}
+	start := rt.o.clock.Now()
+	v, err := call(func() (T, error) { return rt.fn(rt.ctx) })
+	end := rt.o.clock.Now()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if err == nil {
+		if rt.r.TryResolve(v) {
+			rt.stopLocked()
+		}
+		return
+	}
+	if rt.r.resolved() {
+		return
+	}
+	rt.attempts = append(rt.attempts, RetryAttempt{Start: start, Duration: end.Sub(start), Err: err})
+	n := len(rt.attempts)
+	if !rt.policy.retryable(err) || rt.policy.MaxAttempts > 0 && n >= rt.policy.MaxAttempts {
+		rt.failLocked(err)
+		return
+	}
+	delay := rt.policy.delay(n)
+	if rt.policy.MaxElapsed > 0 && end.Add(delay).Sub(rt.start) > rt.policy.MaxElapsed {
+		rt.failLocked(err)
+		return
+	}
+	rt.timer = rt.o.clock.AfterFunc(delay, rt.run)
+}
+
+func (rt *retry[T]) fail(err error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.timer != nil {
+		rt.timer.Stop()
+	}
+	if rt.r.tryCancel(rt.errorLocked(err)) {
+		rt.stopLocked()
+	}
+}
+
+func (rt *retry[T]) failLocked(err error) {
+	if rt.r.TryReject(rt.errorLocked(err)) {
+		rt.stopLocked()
+	}
+}
+
+func (rt *retry[T]) stopLocked() {
+	if rt.stop != nil {
+		rt.stop()
+	}
+}
+
+func (rt *retry[T]) errorLocked(err error) *RetryError {
+	return &RetryError{Attempts: slices.Clone(rt.attempts), Err: err}
+}
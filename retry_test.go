@@ -0,0 +1,190 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+// Failing returns a function that fails the given number of times and then returns its attempt number. Every attempt advances the clock by a second.
+func Failing(clock *fakeclock.Clock, failures int) (fn func(context.Context) (int, error), attempts func() int) {
+	n := 0
+	mu := sync.Mutex{}
+	fn = func(context.Context) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		clock.Advance(time.Second)
+		if n <= failures {
+			return 0, errTest
+		}
+		return n, nil
+	}
+	attempts = func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return n
+	}
+	return fn, attempts
+}
+
+func RetryErrorOf(t *testing.T, err error) *future.RetryError {
+	t.Helper()
+	re := &future.RetryError{}
+	if !errors.As(err, &re) {
+		t.Fatalf("retry failed with %v, expected *RetryError", err)
+	}
+	return re
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, attempts := Failing(clock, 2)
+	policy := future.RetryPolicy{InitialDelay: time.Minute, Multiplier: 3}
+	f := future.Retry(t.Context(), policy, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+
+	if attempts() != 1 {
+		t.Errorf("retry made %d attempts before advancing the clock, expected 1", attempts())
+	}
+	clock.Advance(time.Minute - time.Nanosecond)
+	if attempts() != 1 || IsAwaitableDone(f) {
+		t.Errorf("retry made %d attempts before the delay elapsed, expected 1", attempts())
+	}
+	clock.Advance(time.Nanosecond)
+	if attempts() != 2 {
+		t.Errorf("retry made %d attempts after the first delay, expected 2", attempts())
+	}
+	clock.Advance(3 * time.Minute)
+	if v, err := f.Result(); v != 3 || err != nil {
+		t.Errorf("retry returned (%d, %v), expected (3, nil)", v, err)
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, attempts := Failing(clock, 10)
+	policy := future.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: time.Second}
+	f := future.Retry(t.Context(), policy, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+	clock.Advance(time.Hour)
+
+	re := RetryErrorOf(t, f.Err())
+	if attempts() != 3 || len(re.Attempts) != 3 {
+		t.Fatalf("retry made %d attempts and recorded %d, expected 3", attempts(), len(re.Attempts))
+	}
+	for i, a := range re.Attempts {
+		if !errors.Is(a.Err, errTest) || a.Duration != time.Second {
+			t.Errorf("attempt %d recorded with (%v, %v), expected (%v, 1s)", i, a.Err, a.Duration, errTest)
+		}
+		if i > 0 && a.Start.Sub(re.Attempts[i-1].Start) != 2*time.Second {
+			t.Errorf("attempt %d started %v after the previous one, expected 2s", i, a.Start.Sub(re.Attempts[i-1].Start))
+		}
+	}
+	if !errors.Is(f.Err(), errTest) {
+		t.Errorf("retry error does not unwrap to the last attempt error")
+	}
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, attempts := Failing(clock, 10)
+	policy := future.RetryPolicy{MaxElapsed: 9 * time.Second, InitialDelay: time.Second}
+	f := future.Retry(t.Context(), policy, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+	clock.Advance(time.Hour)
+
+	// attempts start at 0s, 2s (1s delay) and 5s (2s delay), the next one would start at 10s (4s delay), after the limit
+	if re := RetryErrorOf(t, f.Err()); attempts() != 3 || len(re.Attempts) != 3 {
+		t.Errorf("retry made %d attempts and recorded %d, expected 3", attempts(), len(re.Attempts))
+	}
+}
+
+func TestRetryIf(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, attempts := Failing(clock, 10)
+	policy := future.RetryPolicy{RetryIf: func(err error) bool { return !errors.Is(err, errTest) }}
+	f := future.Retry(t.Context(), policy, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+
+	if re := RetryErrorOf(t, f.Err()); attempts() != 1 || len(re.Attempts) != 1 {
+		t.Errorf("retry made %d attempts of not retryable error, expected 1", attempts())
+	}
+}
+
+func TestRetryPanic(t *testing.T) {
+	t.Parallel()
+
+	f := future.Retry(t.Context(), future.RetryPolicy{}, func(context.Context) (int, error) { panic("boom") })
+	if pe := (&future.PanicError{}); !errors.As(f.Err(), &pe) {
+		t.Errorf("panicked retry rejected with %v, expected *PanicError", f.Err())
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, _ := Failing(clock, 100)
+	policy := future.RetryPolicy{MaxAttempts: 100, InitialDelay: 10 * time.Second, MaxDelay: 10 * time.Second, Jitter: 0.5}
+	f := future.Retry(t.Context(), policy, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+	clock.Advance(time.Hour)
+
+	re := RetryErrorOf(t, f.Err())
+	delays := map[time.Duration]bool{}
+	for i := 1; i < len(re.Attempts); i++ {
+		d := re.Attempts[i].Start.Sub(re.Attempts[i-1].Start) - re.Attempts[i-1].Duration
+		if d < 5*time.Second || d > 15*time.Second {
+			t.Errorf("delay before attempt %d is %v, expected between 5s and 15s", i, d)
+		}
+		delays[d] = true
+	}
+	if len(delays) < 2 {
+		t.Errorf("all delays are equal, expected them to be randomized")
+	}
+}
+
+var errCancel = errors.New("cancel cause")
+
+func TestRetryCancel(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	fn, attempts := Failing(clock, 10)
+	ctx, cancel := context.WithCancelCause(t.Context())
+	f := future.Retry(ctx, future.RetryPolicy{}, fn, future.WithExecutor(future.Inline{}), future.WithClock(clock))
+
+	cancel(errCancel)
+	re := RetryErrorOf(t, f.Err())
+	if !errors.Is(re, errCancel) || len(re.Attempts) != 1 {
+		t.Errorf("cancelled retry rejected with %v after %d recorded attempts, expected cancellation cause after 1", re, len(re.Attempts))
+	}
+	clock.Advance(time.Hour)
+	if attempts() != 1 {
+		t.Errorf("retry made %d attempts after context cancel, expected 1", attempts())
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(t.Context())
+	cancel(errCancel)
+	for _, ex := range []future.Executor{future.Inline{}, future.Unbounded{}} {
+		for range 1000 { // the context watch races with the start of retrying
+			f := future.Retry(ctx, future.RetryPolicy{}, func(context.Context) (int, error) { return 1, nil }, future.WithExecutor(ex))
+			if re := RetryErrorOf(t, f.Err()); !errors.Is(re, errCancel) || len(re.Attempts) != 0 {
+				t.Fatalf("retry with cancelled context rejected with %v after %d recorded attempts, expected cancellation cause after none", re, len(re.Attempts))
+			}
+		}
+	}
+}
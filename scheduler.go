@@ -0,0 +1,173 @@
+package future
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrMissedDeadline is the error used to cancel tasks that were not started before their deadline.
+var ErrMissedDeadline = errors.New("future: task deadline passed before it was started")
+
+// Task is a unit of work with scheduling attributes, submitted to a TaskExecutor.
+type Task struct {
+	Run      func()      // function to run
+	Cancel   func(error) // called with the cause instead of Run when the task is dropped, may be nil
+	Priority int         // tasks with higher priority are started first
+	Deadline time.Time   // time by which the task must be started, zero means no deadline
+}
+
+// TaskExecutor is an executor that accepts tasks with scheduling attributes. Helpers in this package submit tasks to executors implementing this interface with the priority and deadline set through options.
+type TaskExecutor interface {
+	Executor
+
+	// Submit submits task for execution. The executor must either run the task or cancel it.
+	Submit(t Task)
+}
+
+// Scheduler is an executor that runs tasks on a fixed maximum number of goroutines and, when all workers are busy, picks the next task to start by its attributes. Tasks with higher priority are started first, tasks of equal priority are started in earliest deadline first order (tasks without deadline last) and remaining ties are broken by submission order.
+//
+// Tasks that are still queued when their deadline passes are not run - they are removed from the queue and cancelled with ErrMissedDeadline at the deadline, measured by the clock of the scheduler.
+//
+// Just like Pool, the scheduler starts its workers on demand and holds no goroutines when idle.
+type Scheduler struct {
+	clock Clock
+
+	mu      sync.Mutex
+	size    int
+	running int
+	seq     uint64
+	queue   taskHeap
+}
+
+// NewScheduler creates a new scheduler that runs at most size tasks concurrently. Out of the given options, only WithClock is used - to time deadlines of tasks. It panics if size is not positive.
+func NewScheduler(size int, opts ...Option) *Scheduler {
+	if size < 1 {
+		panic("future: scheduler size must be positive")
+	}
+	return &Scheduler{clock: newOptions(opts).clock, size: size}
+}
+
+// Go submits task with the default priority of 0 and no deadline.
+func (s *Scheduler) Go(task func()) {
+	s.Submit(Task{Run: task})
+}
+
+// Submit queues task for execution on one of the scheduler workers.
+func (s *Scheduler) Submit(t Task) {
+	s.mu.Lock()
+	s.seq++
+	st := &scheduledTask{Task: t, seq: s.seq}
+	heap.Push(&s.queue, st)
+	if !t.Deadline.IsZero() {
+		st.timer = s.clock.AfterFunc(t.Deadline.Sub(s.clock.Now()), func() { s.expire(st) })
+	}
+	spawn := s.running < s.size
+	if spawn {
+		s.running++
+	}
+	s.mu.Unlock()
+	if spawn {
+		go s.work()
+	}
+}
+
+// Stats returns a snapshot of the load of the scheduler.
+func (s *Scheduler) Stats() ExecutorStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ExecutorStats{Workers: s.size, Busy: s.running, Queued: s.queue.Len()}
+}
+
+func (s *Scheduler) work() {
+	for {
+		s.mu.Lock()
+		if s.queue.Len() == 0 {
+			s.queue = nil
+			s.running--
+			s.mu.Unlock()
+			return
+		}
+		t := heap.Pop(&s.queue).(*scheduledTask) //nolint:forcetypeassert // heap contains only scheduled tasks
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+		s.mu.Unlock()
+		if !t.Deadline.IsZero() && s.clock.Now().After(t.Deadline) { // the timer may have not fired yet
+			t.cancel()
+			continue
+		}
+		t.Run()
+	}
+}
+
+// expire removes the task from the queue and cancels it, unless it was already picked by a worker.
+func (s *Scheduler) expire(t *scheduledTask) {
+	s.mu.Lock()
+	if t.index < 0 {
+		s.mu.Unlock()
+		return
+	}
+	heap.Remove(&s.queue, t.index)
+	s.mu.Unlock()
+	t.cancel()
+}
+
+type scheduledTask struct {
+	Task
+	seq   uint64
+	index int   // index in the queue, -1 once removed from it
+	timer Timer // fires at the deadline of the task, nil if it has none
+}
+
+func (t *scheduledTask) cancel() {
+	if t.Cancel != nil {
+		t.Cancel(ErrMissedDeadline)
+	}
+}
+
+type taskHeap []*scheduledTask
+
+func (h *taskHeap) Len() int {
+	return len(*h)
+}
+
+func (h *taskHeap) Less(i, j int) bool {
+	a, b := (*h)[i], (*h)[j]
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if !a.Deadline.Equal(b.Deadline) {
+		switch {
+		case a.Deadline.IsZero():
+			return false
+		case b.Deadline.IsZero():
+			return true
+		default:
+			return a.Deadline.Before(b.Deadline)
+		}
+	}
+	return a.seq < b.seq
+}
+
+func (h *taskHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+	(*h)[i].index, (*h)[j].index = i, j
+}
+
+func (h *taskHeap) Push(x any) {
+	t := x.(*scheduledTask) //nolint:forcetypeassert // heap contains only scheduled tasks
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	x.index = -1
+	*h = old[:n-1]
+	return x
+}
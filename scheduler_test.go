@@ -0,0 +1,150 @@
+package future_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+	"github.com/daishe/go-future/futuretest"
+)
+
+type OrderRecorder struct {
+	mu    sync.Mutex
+	order []int
+}
+
+func (r *OrderRecorder) Record(v int) func() int {
+	return func() int {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.order = append(r.order, v)
+		return v
+	}
+}
+
+func (r *OrderRecorder) Order() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.order)
+}
+
+// BlockScheduler occupies the only worker of the given scheduler until the returned start condition is started.
+func BlockScheduler(s *future.Scheduler) futuretest.StartCond {
+	started, release := futuretest.NewStartCond(), futuretest.NewStartCond()
+	s.Go(func() {
+		started.Start()
+		release.Wait()
+	})
+	started.Wait()
+	return release
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	t.Parallel()
+
+	s := future.NewScheduler(1)
+	r := &OrderRecorder{}
+	release := BlockScheduler(s)
+
+	fs := []*future.Future[int]{
+		future.Go(r.Record(1), future.WithExecutor(s), future.WithPriority(1)),
+		future.Go(r.Record(3), future.WithExecutor(s), future.WithPriority(3)),
+		future.Go(r.Record(0), future.WithExecutor(s)),
+		future.Go(r.Record(2), future.WithExecutor(s), future.WithPriority(2)),
+	}
+	release.Start()
+	for _, f := range fs {
+		f.Wait()
+	}
+
+	if got, expected := r.Order(), []int{3, 2, 1, 0}; !slices.Equal(got, expected) {
+		t.Errorf("tasks run in order %v, expected %v", got, expected)
+	}
+}
+
+func TestSchedulerEarliestDeadlineFirst(t *testing.T) {
+	t.Parallel()
+
+	s := future.NewScheduler(1)
+	r := &OrderRecorder{}
+	release := BlockScheduler(s)
+
+	now := time.Now()
+	fs := []*future.Future[int]{
+		future.Go(r.Record(0), future.WithExecutor(s)),
+		future.Go(r.Record(3), future.WithExecutor(s), future.WithDeadline(now.Add(3*time.Hour))),
+		future.Go(r.Record(1), future.WithExecutor(s), future.WithDeadline(now.Add(1*time.Hour))),
+		future.Go(r.Record(2), future.WithExecutor(s), future.WithDeadline(now.Add(2*time.Hour))),
+		future.Go(r.Record(4), future.WithExecutor(s), future.WithPriority(1)),
+	}
+	release.Start()
+	for _, f := range fs {
+		f.Wait()
+	}
+
+	if got, expected := r.Order(), []int{4, 1, 2, 3, 0}; !slices.Equal(got, expected) {
+		t.Errorf("tasks run in order %v, expected %v", got, expected)
+	}
+}
+
+func TestSchedulerMissedDeadline(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	s := future.NewScheduler(1, future.WithClock(clock))
+	r := &OrderRecorder{}
+	release := BlockScheduler(s)
+
+	late := future.Go(r.Record(1), future.WithExecutor(s), future.WithDeadline(clock.Now().Add(time.Second)))
+	onTime := future.Go(r.Record(2), future.WithExecutor(s), future.WithDeadline(clock.Now().Add(time.Hour)))
+	clock.Advance(time.Minute)
+	release.Start()
+
+	if err := late.Err(); !errors.Is(err, future.ErrMissedDeadline) {
+		t.Errorf("late task future rejected with %v, expected %v", err, future.ErrMissedDeadline)
+	}
+	if v, err := onTime.Result(); err != nil || v != 2 {
+		t.Errorf("on time task future resolved with (%v, %v), expected (2, nil)", v, err)
+	}
+	if got, expected := r.Order(), []int{2}; !slices.Equal(got, expected) {
+		t.Errorf("tasks run in order %v, expected %v", got, expected)
+	}
+}
+
+func TestSchedulerMissedDeadlineWhileBusy(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	s := future.NewScheduler(1, future.WithClock(clock))
+	r := &OrderRecorder{}
+	release := BlockScheduler(s)
+	defer release.Start()
+
+	late := future.Go(r.Record(1), future.WithExecutor(s), future.WithDeadline(clock.Now().Add(time.Second)))
+	clock.Advance(time.Second)
+	if _, done := futuretest.IsDone(late); !done {
+		t.Fatalf("late task future pending after its deadline, while the scheduler is busy")
+	}
+	if err := late.Err(); !errors.Is(err, future.ErrMissedDeadline) {
+		t.Errorf("late task future rejected with %v, expected %v", err, future.ErrMissedDeadline)
+	}
+	if st := s.Stats(); st.Queued != 0 {
+		t.Errorf("%d tasks queued after the deadline, expected none", st.Queued)
+	}
+	if n := clock.Timers(); n != 0 {
+		t.Errorf("%d timers scheduled after the deadline, expected none", n)
+	}
+}
+
+func TestMissedDeadlineWithoutScheduler(t *testing.T) {
+	t.Parallel()
+
+	f := future.Go(func() int { return 1 }, future.WithDeadline(time.Now().Add(-time.Second)))
+	if err := f.Err(); !errors.Is(err, future.ErrMissedDeadline) {
+		t.Errorf("future rejected with %v, expected %v", err, future.ErrMissedDeadline)
+	}
+}
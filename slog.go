@@ -0,0 +1,41 @@
+package future
+
+import (
+	"log/slog"
+)
+
+var _ slog.LogValuer = (*Future[int])(nil)
+
+// LogValue describes the future for log/slog, instead of a raw pointer: it is a group holding the state of the future ("pending", "resolved" or "rejected") and, once the future is settled, its value or the error it was rejected with. Futures created while debugging facilities or hooks are enabled (see New) are described with their identifier, name and parent identifier too.
+//
+// The value is read only after the future is settled, so logging a pending future never races with its resolution.
+func (f *Future[T]) LogValue() slog.Value {
+	if f == nil {
+		return slog.StringValue("<nil>")
+	}
+	attrs := make([]slog.Attr, 0, 5) //nolint:mnd // identifier, name, parent identifier, state and result
+	if m := f.m; m != nil {
+		attrs = append(attrs, m.logAttrs()...)
+	}
+	switch {
+	case !f.resolved():
+		attrs = append(attrs, slog.String("state", FuturePending.String()))
+	case f.err != nil:
+		attrs = append(attrs, slog.String("state", FutureRejected.String()), slog.Any("error", f.err))
+	default:
+		attrs = append(attrs, slog.String("state", FutureResolved.String()), slog.Any("value", f.v))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logAttrs returns attributes identifying the future.
+func (m *meta) logAttrs() []slog.Attr {
+	attrs := []slog.Attr{slog.Uint64("id", m.id)}
+	if m.name != "" {
+		attrs = append(attrs, slog.String("name", m.name))
+	}
+	if m.parent != 0 {
+		attrs = append(attrs, slog.Uint64("parent_id", m.parent))
+	}
+	return attrs
+}
@@ -0,0 +1,55 @@
+package future_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/daishe/go-future"
+)
+
+func logged(v any) string {
+	b := &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey {
+			return slog.Attr{}
+		}
+		return a
+	}})).Info("", "f", v)
+	return strings.TrimSpace(b.String())
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	var nilFuture *future.Future[int]
+	cases := []struct {
+		name     string
+		f        *future.Future[int]
+		expected string
+	}{
+		{"pending", &future.Future[int]{}, "f.state=pending"},
+		{"resolved", future.Resolved(1), "f.state=resolved f.value=1"},
+		{"rejected", future.Rejected[int](errTest), `f.state=rejected f.error="test error"`},
+		{"nil", nilFuture, "f=<nil>"},
+	}
+	for _, c := range cases {
+		if s := logged(c.f); s != c.expected {
+			t.Errorf("%s future logged as %q, expected %q", c.name, s, c.expected)
+		}
+	}
+}
+
+func TestLogValueMeta(t *testing.T) { //nolint:paralleltest // the registry is process wide
+	disable := future.EnableRegistry()
+	defer disable()
+
+	f := future.New[string](future.WithName("source"))
+	g := future.Then(f, func(v string) (string, error) { return v, nil }, future.WithName("then"))
+	f.Resolve("x")
+	g.Wait()
+	if s := logged(g); !strings.Contains(s, "f.name=then f.parent_id=") || !strings.HasSuffix(s, "f.state=resolved f.value=x") {
+		t.Errorf("future logged as %q, expected with name, parent identifier, state and value", s)
+	}
+}
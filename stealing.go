@@ -0,0 +1,254 @@
+package future
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// pendingTask is a task submitted to an executor that can be claimed (and run) exactly once, either by one of the executor workers or by a goroutine that waits for the result of the task.
+type pendingTask struct {
+	claimed atomic.Bool
+	ex      inliningExecutor // executor the task was submitted to
+	queued  *atomic.Int64    // queued tasks counter of the executor, decremented when the task is claimed
+	fn      func()
+	inline  func() // run instead of fn by goroutines that wait for the result of the task, nil means fn
+}
+
+func (t *pendingTask) claim() bool {
+	if !t.claimed.CompareAndSwap(false, true) {
+		return false
+	}
+	t.queued.Add(-1)
+	return true
+}
+
+// runInline claims and runs the task in the calling goroutine, unless it was already claimed.
+func (t *pendingTask) runInline() {
+	if !t.claim() {
+		return
+	}
+	if t.inline != nil {
+		t.inline()
+		return
+	}
+	t.fn()
+}
+
+// inliningExecutor is implemented by executors that allow goroutines waiting for the result of a queued task to claim it and run it themselves.
+type inliningExecutor interface {
+	Executor
+	newPending(task func()) *pendingTask
+	goPending(t *pendingTask)
+	// await is called by goroutines that wait for the result of the task, before they park until done is closed.
+	await(t *pendingTask, done <-chan struct{})
+}
+
+// WorkStealing is an executor designed for fine-grained, recursive (divide-and-conquer) workloads. It runs tasks on a fixed maximum number of workers, each with its own double-ended queue. Tasks submitted by a worker (from within a running task) are pushed to its own queue, other tasks are spread over the worker queues. Every worker runs tasks from its own queue in last-in first-out order - so subtasks run close to the task that created them - and, when it runs out of them, steals the oldest tasks from other workers' queues.
+//
+// A goroutine that waits (see Future.Wait and Future.Get) for a future created by Go with this executor, does not park if the task of the future was not yet started by any worker. Instead, it claims the task and runs it itself. If the task was already started, a waiting worker keeps running other queued tasks until the future is resolved, and parks only while there are none. This makes nested waits inside tasks safe - a worker waiting for the result of another task never starves the executor of workers.
+//
+// Just like Pool, the executor starts its workers on demand and holds no goroutines when idle.
+type WorkStealing struct {
+	workers []*stealingWorker
+	next    atomic.Uint64 // worker queue that receives the next submitted task
+	queued  atomic.Int64  // number of unclaimed queued tasks, across all queues
+	idle    atomic.Int64  // number of workers without running goroutine, only modified under mu
+	helpers atomic.Int64  // number of waiting workers parked until a task is queued
+
+	mu   sync.Mutex
+	free []*stealingWorker // workers without running goroutine
+
+	helpMu sync.Mutex
+	wake   chan struct{} // closed when a task is queued while waiting workers are parked, nil if none are
+}
+
+type stealingWorker struct {
+	ws    *WorkStealing
+	gid   atomic.Uint64 // identifier of the goroutine of the worker, zero if it is not running
+	mu    sync.Mutex
+	deque []*pendingTask
+}
+
+// NewWorkStealing creates a new work stealing executor with at most size workers. It panics if size is not positive.
+func NewWorkStealing(size int) *WorkStealing {
+	if size < 1 {
+		panic("future: work stealing executor size must be positive")
+	}
+	ws := &WorkStealing{
+		workers: make([]*stealingWorker, size),
+		free:    make([]*stealingWorker, size),
+	}
+	for i := range ws.workers {
+		ws.workers[i] = &stealingWorker{ws: ws}
+		ws.free[i] = ws.workers[i]
+	}
+	ws.idle.Store(int64(size))
+	return ws
+}
+
+// Go queues task for execution on one of the executor workers.
+func (ws *WorkStealing) Go(task func()) {
+	ws.goPending(ws.newPending(task))
+}
+
+// Stats returns a snapshot of the load of the executor. Tasks run by goroutines waiting for their results are not counted as queued nor busy.
+func (ws *WorkStealing) Stats() ExecutorStats {
+	return ExecutorStats{Workers: len(ws.workers), Busy: len(ws.workers) - int(ws.idle.Load()), Queued: int(ws.queued.Load())}
+}
+
+func (ws *WorkStealing) newPending(task func()) *pendingTask {
+	return &pendingTask{ex: ws, queued: &ws.queued, fn: task}
+}
+
+// current returns the worker running in the calling goroutine, if any. Identifying the goroutine requires a stack trace, so it is relatively costly.
+func (ws *WorkStealing) current() *stealingWorker {
+	id := goid()
+	for _, w := range ws.workers {
+		if w.gid.Load() == id {
+			return w
+		}
+	}
+	return nil
+}
+
+func (ws *WorkStealing) goPending(t *pendingTask) {
+	var w *stealingWorker
+	if ws.idle.Load() < int64(len(ws.workers)) { // no worker runs the calling goroutine while all are idle
+		w = ws.current()
+	}
+	if w == nil {
+		w = ws.workers[ws.next.Add(1)%uint64(len(ws.workers))]
+	}
+	w.mu.Lock()
+	w.deque = append(w.deque, t)
+	w.mu.Unlock()
+	ws.queued.Add(1) // after the push, so that workers never spin on a count of tasks they cannot find yet
+
+	if ws.helpers.Load() > 0 {
+		ws.helpMu.Lock()
+		if ws.wake != nil {
+			close(ws.wake)
+			ws.wake = nil
+		}
+		ws.helpMu.Unlock()
+	}
+	if ws.idle.Load() == 0 {
+		return
+	}
+	ws.mu.Lock()
+	if n := len(ws.free); n > 0 {
+		w := ws.free[n-1]
+		ws.free = ws.free[:n-1]
+		ws.idle.Add(-1)
+		go w.run()
+	}
+	ws.mu.Unlock()
+}
+
+func (ws *WorkStealing) await(t *pendingTask, done <-chan struct{}) {
+	t.runInline()
+	if isDone(done) {
+		return
+	}
+	w := ws.current()
+	if w == nil {
+		return
+	}
+	for !isDone(done) {
+		if u := w.find(); u != nil {
+			u.fn()
+			continue
+		}
+		ws.helpMu.Lock()
+		if ws.wake == nil {
+			ws.wake = make(chan struct{})
+		}
+		wake := ws.wake
+		ws.helpMu.Unlock()
+		// Count the worker as parked before the final check for queued work - submitters increment the count of queued tasks before checking for parked workers, so one of both sides always notices the other.
+		ws.helpers.Add(1)
+		if ws.queued.Load() == 0 {
+			select {
+			case <-done:
+			case <-wake:
+			}
+		}
+		ws.helpers.Add(-1)
+	}
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *stealingWorker) pop() *pendingTask {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for n := len(w.deque); n > 0; n-- {
+		t := w.deque[n-1]
+		w.deque[n-1] = nil
+		w.deque = w.deque[:n-1]
+		if t.claim() {
+			return t
+		}
+	}
+	w.deque = nil
+	return nil
+}
+
+func (w *stealingWorker) steal() *pendingTask {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.deque) > 0 {
+		t := w.deque[0]
+		w.deque[0] = nil
+		w.deque = w.deque[1:]
+		if t.claim() {
+			return t
+		}
+	}
+	w.deque = nil
+	return nil
+}
+
+// find claims the next task for the worker to run, looking at its own queue first and then trying to steal from other workers. It returns nil if there is no queued work.
+func (w *stealingWorker) find() *pendingTask {
+	t := w.pop()
+	for i := 0; t == nil && i < len(w.ws.workers); i++ {
+		if v := w.ws.workers[i]; v != w {
+			t = v.steal()
+		}
+	}
+	return t
+}
+
+func (w *stealingWorker) run() {
+	ws := w.ws
+	id := goid()
+	w.gid.Store(id)
+	for {
+		if t := w.find(); t != nil {
+			t.fn()
+			continue
+		}
+		// Mark the worker as idle before the final check for queued work - submitters increment the count of queued tasks before checking for idle workers, so one of both sides always notices the other.
+		ws.mu.Lock()
+		w.gid.Store(0) // before the worker is free, as its next goroutine may start right away
+		ws.free = append(ws.free, w)
+		ws.idle.Add(1)
+		if ws.queued.Load() > 0 {
+			ws.free = ws.free[:len(ws.free)-1]
+			ws.idle.Add(-1)
+			w.gid.Store(id)
+			ws.mu.Unlock()
+			continue
+		}
+		ws.mu.Unlock()
+		return
+	}
+}
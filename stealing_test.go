@@ -0,0 +1,98 @@
+package future_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+)
+
+func ParallelSum(ex future.Executor, from, to int) int {
+	if to-from <= 4 {
+		sum := 0
+		for i := from; i < to; i++ {
+			sum += i
+		}
+		return sum
+	}
+	mid := from + (to-from)/2
+	left := future.Go(func() int { return ParallelSum(ex, from, mid) }, future.WithExecutor(ex))
+	right := future.Go(func() int { return ParallelSum(ex, mid, to) }, future.WithExecutor(ex))
+	return left.Get() + right.Get()
+}
+
+func TestWorkStealingNested(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{1, 2, 8} {
+		ws := future.NewWorkStealing(size)
+		got := future.Go(func() int { return ParallelSum(ws, 0, 10000) }, future.WithExecutor(ws)).Get()
+		if expected := 10000 * 9999 / 2; got != expected {
+			t.Errorf("parallel sum on %d workers returned %d, expected %d", size, got, expected)
+		}
+	}
+}
+
+func TestWorkStealingBound(t *testing.T) {
+	t.Parallel()
+
+	m := &ConcurrencyMeter{}
+	fs := RunMetered(future.NewWorkStealing(3), m, 1, 50)
+	AllResolvedInOrder(t, fs)
+	if m.Max() > 3 {
+		t.Errorf("work stealing executor ran %d tasks concurrently, expected at most 3", m.Max())
+	}
+}
+
+func TestWorkStealingWaitOnExternal(t *testing.T) {
+	t.Parallel()
+
+	ws := future.NewWorkStealing(1)
+	external := &future.Future[int]{}
+	got := future.Go(func() int { return external.Get() + 1 }, future.WithExecutor(ws))
+	other := future.Go(func() int { return 2 }, future.WithExecutor(ws))
+
+	if v := other.Get(); v != 2 {
+		t.Errorf("other task resolved with %d, expected 2", v)
+	}
+	external.Resolve(1)
+	if v := got.Get(); v != 2 {
+		t.Errorf("waiting task resolved with %d, expected 2", v)
+	}
+}
+
+func BenchmarkParallelSum(b *testing.B) {
+	b.Run("WorkStealing", func(b *testing.B) {
+		ws := future.NewWorkStealing(8)
+		for b.Loop() {
+			future.Go(func() int { return ParallelSum(ws, 0, 1<<14) }, future.WithExecutor(ws)).Get()
+		}
+	})
+	b.Run("Unbounded", func(b *testing.B) {
+		for b.Loop() {
+			ParallelSum(future.Unbounded{}, 0, 1<<14)
+		}
+	})
+}
+
+func TestWorkStealingWaitOnStarted(t *testing.T) {
+	t.Parallel()
+
+	ws := future.NewWorkStealing(2)
+	got := future.Go(func() int {
+		started, release := make(chan struct{}), make(chan struct{})
+		blocked := future.Go(func() int { close(started); <-release; return 1 }, future.WithExecutor(ws))
+		<-started // the other worker runs the task, so waiting for it cannot run it inline
+		releasing := future.Go(func() int { close(release); return 2 }, future.WithExecutor(ws))
+		return blocked.Get() + releasing.Get()
+	}, future.WithExecutor(ws))
+
+	select {
+	case <-got.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("worker waiting for a started task did not run other queued tasks")
+	}
+	if v := got.Get(); v != 3 {
+		t.Errorf("task resolved with %d, expected 3", v)
+	}
+}
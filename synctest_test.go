@@ -0,0 +1,152 @@
+//go:build go1.25
+
+package future_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+func TestFutureInBubble(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		f := &future.Future[int]{}
+		t.Track("future", f)
+
+		start := futuretest.NewStartCond()
+		tryResolvers := futuretest.NewResults(start, f, futuretest.TryResolve(1), futuretest.TryResolve(2), futuretest.TryResolve(3))
+		got := futuretest.NewResults(start, f, futuretest.Get, futuretest.WaitAndGet, futuretest.Get)
+
+		synctest.Wait() // all goroutines are parked on the start condition
+		futuretest.NoneMust(t, futuretest.IsSuccessful, futuretest.NewResults(futuretest.NewStartCond(), f))
+		start.Start()
+
+		v := futuretest.FindOne(t, futuretest.IsSuccessful, tryResolvers).Value
+		futuretest.AllMust(t, futuretest.IsValueEqual(v), got)
+	})
+}
+
+func TestTimersInBubble(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		start := time.Now()
+		slow := future.Go(func() int { time.Sleep(time.Hour); return 1 })
+		timedOut := future.WithTimeout(slow, time.Minute)
+		orElse := future.OrElse(slow, time.Minute, 2)
+		t.Track("slow", slow)
+
+		if !errors.Is(timedOut.Err(), future.ErrTimeout) || orElse.Get() != 2 {
+			t.Errorf("timed out futures resolved with (%v, %d), expected (%v, 2)", timedOut.Err(), orElse.Get(), future.ErrTimeout)
+		}
+		if elapsed := time.Since(start); elapsed != time.Minute {
+			t.Errorf("futures timed out after %v, expected exactly 1m", elapsed)
+		}
+		slow.Wait()
+	})
+}
+
+func TestRetryInBubble(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		start := time.Now()
+		attempts := 0
+		f := future.Retry(t.Context(), future.RetryPolicy{InitialDelay: time.Second}, func(context.Context) (int, error) {
+			attempts++
+			if attempts < 4 {
+				return 0, errTest
+			}
+			return attempts, nil
+		}, future.WithExecutor(future.NewPool(1)))
+		t.Track("retry", f)
+
+		if v := f.Get(); v != 4 {
+			t.Errorf("retry resolved with %d, expected 4", v)
+		}
+		if elapsed := time.Since(start); elapsed != 7*time.Second {
+			t.Errorf("retry took %v, expected exactly 7s (1s, 2s and 4s delays)", elapsed)
+		}
+	})
+}
+
+func TestHedgeInBubble(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		start := time.Now()
+		latencies := []time.Duration{time.Hour, time.Second}
+		calls := atomic.Int64{}
+		f := future.Hedge(t.Context(), 100*time.Millisecond, 2, func(ctx context.Context) (time.Duration, error) {
+			latency := latencies[calls.Add(1)-1]
+			select {
+			case <-time.After(latency):
+				return latency, nil
+			case <-ctx.Done():
+				return 0, context.Cause(ctx)
+			}
+		}, future.WithExecutor(future.NewPool(2)))
+		t.Track("hedge", f)
+
+		if v := f.Get(); v != time.Second {
+			t.Errorf("hedge resolved with the attempt taking %v, expected 1s", v)
+		}
+		if elapsed := time.Since(start); elapsed != 1100*time.Millisecond {
+			t.Errorf("hedge took %v, expected exactly 1.1s", elapsed)
+		}
+	})
+}
+
+func TestExecutorsInBubble(t *testing.T) {
+	t.Parallel()
+
+	executors := map[string]func() future.Executor{
+		"pool":         func() future.Executor { return future.NewPool(2) },
+		"weighted":     func() future.Executor { return future.NewWeighted(2) },
+		"scheduler":    func() future.Executor { return future.NewScheduler(2) },
+		"workStealing": func() future.Executor { return future.NewWorkStealing(2) },
+	}
+	for name, newExecutor := range executors {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			futuretest.Settled(t, func(t *futuretest.Scenario) {
+				ex := newExecutor()
+				start := time.Now()
+				for i := range 4 {
+					t.Track("task "+strconv.Itoa(i), future.Go(func() int { time.Sleep(time.Second); return i }, future.WithExecutor(ex)))
+				}
+				synctest.Wait()
+				if elapsed := time.Since(start); elapsed != 0 {
+					t.Errorf("%v elapsed before all tasks were blocked", elapsed)
+				}
+				time.Sleep(2 * time.Second) // two rounds of tasks, sleeping for a second each
+			})
+		})
+	}
+}
+
+func TestEventLoopInBubble(t *testing.T) {
+	t.Parallel()
+
+	futuretest.Settled(t, func(t *futuretest.Scenario) {
+		loop := future.NewEventLoop()
+		external := future.Go(func() int { time.Sleep(time.Second); return 1 })
+		doubled := future.Then(external, func(v int) (int, error) { return 2 * v, nil }, future.WithExecutor(loop))
+		t.Track("doubled", doubled)
+
+		loop.RunUntil(doubled)
+		if v := doubled.Get(); v != 2 {
+			t.Errorf("continuation resolved with %d, expected 2", v)
+		}
+	})
+}
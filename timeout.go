@@ -0,0 +1,66 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeout is the error futures created by WithTimeout are rejected with, if the original future was not resolved in time.
+var ErrTimeout = errors.New("future: timed out")
+
+// WithTimeout returns a future that is resolved with the result of f, if f is resolved within the duration (measured using the configured clock, see WithClock), or rejected with ErrTimeout otherwise. No goroutines are used for waiting.
+func WithTimeout[T any](f *Future[T], d time.Duration, opts ...Option) *Future[T] {
+	return timeout(f, d, newOptions(opts), func(r *Future[T]) {
+		r.TryReject(ErrTimeout)
+	})
+}
+
+// OrElse returns a future that is resolved with the result of f, if f is resolved within the duration (measured using the configured clock, see WithClock), or with the default value otherwise. No goroutines are used for waiting.
+func OrElse[T any](f *Future[T], d time.Duration, def T, opts ...Option) *Future[T] {
+	return timeout(f, d, newOptions(opts), func(r *Future[T]) {
+		r.TryResolve(def)
+	})
+}
+
+func timeout[T any](f *Future[T], d time.Duration, o *options, expire func(r *Future[T])) *Future[T] {
+	r := newFuture[T](context.Background(), o, nil)
+	t := o.clock.AfterFunc(d, func() {
+		expire(r)
+	})
+	f.afterResolve(func() {
+		t.Stop()
+		r.trySettle(f.Result())
+	})
+	return r
+}
+
+// Fallback returns a future that is resolved with the value of the first of the given futures (in the order of the slice) that is not rejected. Every future is awaited only after all of the preceding ones were rejected, so a slow alternative does not delay the result if a preceding one succeeds. If all futures are rejected, the returned future is rejected with all of their errors joined. No goroutines are used for waiting. It panics if no futures are given.
+func Fallback[T any](fs []*Future[T], opts ...Option) *Future[T] {
+	if len(fs) == 0 {
+		panic("future: fallback of no futures")
+	}
+	r := newFuture[T](context.Background(), newOptions(opts), fs[0].m)
+	for _, f := range fs[1:] {
+		r.m.dependOn(f.m)
+	}
+	errs := make([]error, 0, len(fs))
+	var next func(i int)
+	next = func(i int) {
+		fs[i].afterResolve(func() {
+			v, err := fs[i].Result()
+			if err == nil {
+				r.TryResolve(v)
+				return
+			}
+			errs = append(errs, err)
+			if i == len(fs)-1 {
+				r.TryReject(errors.Join(errs...))
+				return
+			}
+			next(i + 1)
+		})
+	}
+	next(0)
+	return r
+}
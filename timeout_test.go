@@ -0,0 +1,103 @@
+package future_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/fakeclock"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	slow, fast := &future.Future[int]{}, &future.Future[int]{}
+	slowT := future.WithTimeout(slow, time.Second, future.WithClock(clock))
+	fastT := future.WithTimeout(fast, time.Second, future.WithClock(clock))
+	resolvedT := future.WithTimeout(future.Resolved(3), time.Second, future.WithClock(clock))
+
+	fast.Resolve(2)
+	clock.Advance(time.Second - time.Nanosecond)
+	if IsAwaitableDone(slowT) {
+		t.Errorf("future with timeout resolved before the timeout")
+	}
+	clock.Advance(time.Nanosecond)
+	if !errors.Is(slowT.Err(), future.ErrTimeout) {
+		t.Errorf("future with timeout rejected with %v, expected %v", slowT.Err(), future.ErrTimeout)
+	}
+	slow.Resolve(1)
+	if slowT.Get() != 0 || fastT.Get() != 2 || resolvedT.Get() != 3 {
+		t.Errorf("futures with timeout resolved with (%d, %d, %d), expected (0, 2, 3)", slowT.Get(), fastT.Get(), resolvedT.Get())
+	}
+}
+
+func TestWithTimeoutRejected(t *testing.T) {
+	t.Parallel()
+
+	f := future.WithTimeout(future.Rejected[int](errTest), time.Second, future.WithClock(fakeclock.New(time.Time{})))
+	if !errors.Is(f.Err(), errTest) {
+		t.Errorf("future with timeout rejected with %v, expected %v", f.Err(), errTest)
+	}
+}
+
+func TestOrElse(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	slow, fast := &future.Future[int]{}, &future.Future[int]{}
+	slowE := future.OrElse(slow, time.Second, -1, future.WithClock(clock))
+	fastE := future.OrElse(fast, time.Second, -1, future.WithClock(clock))
+
+	fast.Resolve(2)
+	clock.Advance(time.Second)
+	slow.Resolve(1)
+	if v, err := slowE.Result(); v != -1 || err != nil {
+		t.Errorf("timed out future resolved with (%d, %v), expected default value", v, err)
+	}
+	if v := fastE.Get(); v != 2 {
+		t.Errorf("future resolved in time resolved with %d, expected 2", v)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := &future.Future[int]{}, &future.Future[int]{}, &future.Future[int]{}
+	f := future.Fallback([]*future.Future[int]{a, b, c})
+
+	c.Resolve(3)
+	if IsAwaitableDone(f) {
+		t.Errorf("fallback resolved with later alternative before preceding ones were rejected")
+	}
+	a.Reject(errTest)
+	if IsAwaitableDone(f) {
+		t.Errorf("fallback resolved before the second alternative was resolved")
+	}
+	b.Resolve(2)
+	if v, err := f.Result(); v != 2 || err != nil {
+		t.Errorf("fallback resolved with (%d, %v), expected (2, nil)", v, err)
+	}
+}
+
+func TestFallbackRejected(t *testing.T) {
+	t.Parallel()
+
+	f := future.Fallback([]*future.Future[int]{future.Rejected[int](errTest), future.Rejected[int](errCancel)})
+	if err := f.Err(); !errors.Is(err, errTest) || !errors.Is(err, errCancel) {
+		t.Errorf("fallback rejected with %v, expected errors of all alternatives", err)
+	}
+}
+
+func TestFallbackWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Time{})
+	primary := &future.Future[int]{}
+	f := future.Fallback([]*future.Future[int]{future.WithTimeout(primary, time.Second, future.WithClock(clock)), future.Resolved(2)})
+	clock.Advance(time.Second)
+	if v := f.Get(); v != 2 {
+		t.Errorf("fallback resolved with %d after primary timed out, expected 2", v)
+	}
+}
@@ -0,0 +1,69 @@
+package future
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// WithTracing makes created futures visible in execution traces and CPU profiles. The lifetime of every future is traced as a runtime/trace task (a subtask of the task of the context, if any), named after the future (see WithName), with goroutines waiting for the future and running the function that produces its result logged as regions of that task. Functions producing results of futures run with the pprof labels of the context (see pprof.WithLabels), when an executor of this package runs them on a goroutine it started (like Pool workers), and the labels are removed once they return. Labels of goroutines that run the functions for their callers - the Inline executor, EventLoop, goroutines waiting for tasks of WorkStealing and executors implemented outside of this package - are left untouched, as runtime/pprof provides no way to restore them afterwards.
+//
+// That way `go tool trace` and CPU profiles show which logical future a goroutine is working for or blocked on. Like other debugging facilities, it makes creation of futures more expensive.
+func WithTracing(ctx context.Context) Option {
+	return func(o *options) {
+		o.traceCtx = ctx
+	}
+}
+
+func (m *meta) startTask(ctx context.Context) {
+	name := m.name
+	if name == "" {
+		name = "future"
+	}
+	m.traceCtx, m.task = trace.NewTask(ctx, name)
+}
+
+func (m *meta) endTask(err error) {
+	if err != nil {
+		trace.Log(m.traceCtx, "rejected", err.Error())
+	} else {
+		trace.Log(m.traceCtx, "resolved", "")
+	}
+	m.task.End()
+}
+
+// ownGoroutines is implemented by executors that run tasks only on goroutines they start, whose pprof labels can be replaced without losing labels of their callers.
+type ownGoroutines interface {
+	ownGoroutines()
+}
+
+func (Unbounded) ownGoroutines()        {}
+func (*Pool) ownGoroutines()            {}
+func (*Weighted) ownGoroutines()        {}
+func (weightedExecutor) ownGoroutines() {}
+func (*Scheduler) ownGoroutines()       {}
+func (*WorkStealing) ownGoroutines()    {}
+
+// labels reports whether functions producing results of futures should run with the pprof labels of the tracing context.
+func (o *options) labels() bool {
+	_, ok := o.executor.(ownGoroutines)
+	return ok && o.traceCtx != nil
+}
+
+// run runs fn, the function producing the result of the future, in the calling goroutine. The pprof labels of the tracing context are set for the duration of fn only if labels is true, that is when the goroutine is owned by the executor.
+func (m *meta) run(labels bool, fn func()) {
+	if m == nil {
+		fn()
+		return
+	}
+	m.produce()
+	if m.traceCtx == nil {
+		fn()
+		return
+	}
+	if labels {
+		pprof.SetGoroutineLabels(m.traceCtx)
+		defer pprof.SetGoroutineLabels(context.Background())
+	}
+	trace.WithRegion(m.traceCtx, "future.Run", fn)
+}
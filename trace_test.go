@@ -0,0 +1,73 @@
+package future_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"testing"
+
+	"github.com/daishe/go-future"
+)
+
+func TestTracing(t *testing.T) { //nolint:paralleltest // tracing is process wide
+	b := &bytes.Buffer{}
+	if err := trace.Start(b); err != nil {
+		t.Skipf("tracing cannot be started: %v", err)
+	}
+	ctx, task := trace.NewTask(t.Context(), "TestTracing")
+	f := future.Go(func() int { return 1 }, future.WithName("traced future"), future.WithTracing(ctx))
+	g := future.Then(f, func(v int) (int, error) { return v, errTest }, future.WithTracing(ctx))
+	g.Wait()
+	task.End()
+	trace.Stop()
+
+	for _, s := range []string{"traced future", "future.Run", "future.Wait", "resolved", "rejected", errTest.Error()} {
+		if !bytes.Contains(b.Bytes(), []byte(s)) {
+			t.Errorf("execution trace does not contain %q", s)
+		}
+	}
+}
+
+// goroutineLabelled reports whether any goroutine is labelled with the given label.
+func goroutineLabelled(tb testing.TB, key, value string) bool {
+	tb.Helper()
+	b := &strings.Builder{}
+	if err := pprof.Lookup("goroutine").WriteTo(b, 1); err != nil {
+		tb.Fatalf("writing goroutine profile: %v", err)
+	}
+	return strings.Contains(b.String(), `"`+key+`":"`+value+`"`)
+}
+
+func TestTracingLabels(t *testing.T) { //nolint:paralleltest // goroutine profile is process wide
+	pool := future.NewPool(1)
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("future", "TestTracingLabels"))
+	started, release := make(chan struct{}), make(chan struct{})
+	f := future.Go(func() int { close(started); <-release; return 1 }, future.WithExecutor(pool), future.WithTracing(ctx))
+	<-started
+	if !goroutineLabelled(t, "future", "TestTracingLabels") {
+		t.Errorf("goroutine running the function of a traced future is not labelled")
+	}
+	close(release)
+	f.Wait()
+
+	started, release = make(chan struct{}), make(chan struct{})
+	g := future.Go(func() int { close(started); <-release; return 1 }, future.WithExecutor(pool))
+	<-started
+	if goroutineLabelled(t, "future", "TestTracingLabels") {
+		t.Errorf("labels of a traced future remained on the goroutine after its function returned")
+	}
+	close(release)
+	g.Wait()
+}
+
+func TestTracingLabelsCaller(t *testing.T) { //nolint:paralleltest // goroutine profile is process wide
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("future", "TestTracingLabelsCaller"))
+	pprof.Do(context.Background(), pprof.Labels("caller", "TestTracingLabelsCaller"), func(context.Context) {
+		future.Go(func() int { return 1 }, future.WithExecutor(future.Inline{}), future.WithTracing(ctx)).Wait() //nolint:contextcheck // the future is traced within ctx, not the context of the caller labels
+		if !goroutineLabelled(t, "caller", "TestTracingLabelsCaller") {
+			t.Errorf("labels of the goroutine running the function of a traced future inline were not preserved")
+		}
+	})
+}
@@ -0,0 +1,324 @@
+package future
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// joiner tracks resolvement of a group of futures, calling resolve once all of them are resolved with values, or reject with the first error.
+type joiner struct {
+	m       *meta // debugging information of the joined future
+	pending atomic.Int64
+	failed  atomic.Bool
+	resolve func()
+	reject  func(error)
+}
+
+// newJoiner creates a joiner that holds an extra pending count, released by start, so that it cannot complete while futures are still being added.
+func newJoiner(m *meta, resolve func(), reject func(error)) *joiner {
+	j := &joiner{m: m, resolve: resolve, reject: reject}
+	j.pending.Store(1)
+	return j
+}
+
+func (j *joiner) start() {
+	j.release()
+}
+
+func (j *joiner) release() {
+	if j.pending.Add(-1) == 0 {
+		j.resolve()
+	}
+}
+
+func joinFuture[T any](j *joiner, f *Future[T]) {
+	j.m.dependOn(f.m)
+	j.pending.Add(1)
+	f.afterResolve(func() {
+		if err := f.Err(); err != nil {
+			if j.failed.CompareAndSwap(false, true) {
+				j.reject(err)
+			}
+			return
+		}
+		j.release()
+	})
+}
+
+// join returns a future resolved with the result of fn called with the value of z, run using the configured executor. The returned future is rejected with the cause of the context cancellation, if the context is cancelled before z is resolved.
+func join[T, R any](ctx context.Context, z *Future[T], fn func(T) (R, error), opts []Option) *Future[R] {
+	o := newOptions(opts)
+	r := newFuture[R](ctx, o, z.m)
+	stop := context.AfterFunc(ctx, func() {
+		r.tryCancel(context.Cause(ctx))
+	})
+	z.afterResolve(func() { //nolint:contextcheck // fn runs within the trace task of the future (see WithTracing), not the context of the join
+		if !stop() {
+			return // context was cancelled first
+		}
+		v, err := z.Result()
+		if err != nil {
+			r.Reject(err)
+			return
+		}
+		labels := o.labels()
+		o.submit(func() {
+			r.m.run(labels, func() {
+				r.settle(call(func() (R, error) { return fn(v) }))
+			})
+		}, r.cancel)
+	})
+	return r
+}
+
+// Tuple2 holds 2 values of possibly different types.
+type Tuple2[A, B any] struct {
+	V1 A
+	V2 B
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple2[A, B]) Unpack() (v1 A, v2 B) {
+	return t.V1, t.V2
+}
+
+// Tuple3 holds 3 values of possibly different types.
+type Tuple3[A, B, C any] struct {
+	V1 A
+	V2 B
+	V3 C
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple3[A, B, C]) Unpack() (v1 A, v2 B, v3 C) {
+	return t.V1, t.V2, t.V3
+}
+
+// Tuple4 holds 4 values of possibly different types.
+type Tuple4[A, B, C, D any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple4[A, B, C, D]) Unpack() (v1 A, v2 B, v3 C, v4 D) {
+	return t.V1, t.V2, t.V3, t.V4
+}
+
+// Tuple5 holds 5 values of possibly different types.
+type Tuple5[A, B, C, D, E any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple5[A, B, C, D, E]) Unpack() (v1 A, v2 B, v3 C, v4 D, v5 E) {
+	return t.V1, t.V2, t.V3, t.V4, t.V5
+}
+
+// Tuple6 holds 6 values of possibly different types.
+type Tuple6[A, B, C, D, E, F any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple6[A, B, C, D, E, F]) Unpack() (v1 A, v2 B, v3 C, v4 D, v5 E, v6 F) { //nolint:gocritic // tuples unpack into all of their values
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6
+}
+
+// Tuple7 holds 7 values of possibly different types.
+type Tuple7[A, B, C, D, E, F, G any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+	V7 G
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple7[A, B, C, D, E, F, G]) Unpack() (v1 A, v2 B, v3 C, v4 D, v5 E, v6 F, v7 G) { //nolint:gocritic // tuples unpack into all of their values
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7
+}
+
+// Tuple8 holds 8 values of possibly different types.
+type Tuple8[A, B, C, D, E, F, G, H any] struct {
+	V1 A
+	V2 B
+	V3 C
+	V4 D
+	V5 E
+	V6 F
+	V7 G
+	V8 H
+}
+
+// Unpack returns all values of the tuple.
+func (t Tuple8[A, B, C, D, E, F, G, H]) Unpack() (v1 A, v2 B, v3 C, v4 D, v5 E, v6 F, v7 G, v8 H) { //nolint:gocritic // tuples unpack into all of their values
+	return t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7, t.V8
+}
+
+// Zip2 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip2[A, B any](a *Future[A], b *Future[B], opts ...Option) *Future[Tuple2[A, B]] {
+	r := newFuture[Tuple2[A, B]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple2[A, B]{a.v, b.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	j.start()
+	return r
+}
+
+// Zip3 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip3[A, B, C any](a *Future[A], b *Future[B], c *Future[C], opts ...Option) *Future[Tuple3[A, B, C]] {
+	r := newFuture[Tuple3[A, B, C]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple3[A, B, C]{a.v, b.v, c.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	j.start()
+	return r
+}
+
+// Zip4 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip4[A, B, C, D any](a *Future[A], b *Future[B], c *Future[C], d *Future[D], opts ...Option) *Future[Tuple4[A, B, C, D]] {
+	r := newFuture[Tuple4[A, B, C, D]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple4[A, B, C, D]{a.v, b.v, c.v, d.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	joinFuture(j, d)
+	j.start()
+	return r
+}
+
+// Zip5 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip5[A, B, C, D, E any](a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], opts ...Option) *Future[Tuple5[A, B, C, D, E]] {
+	r := newFuture[Tuple5[A, B, C, D, E]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple5[A, B, C, D, E]{a.v, b.v, c.v, d.v, e.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	joinFuture(j, d)
+	joinFuture(j, e)
+	j.start()
+	return r
+}
+
+// Zip6 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip6[A, B, C, D, E, F any](a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], opts ...Option) *Future[Tuple6[A, B, C, D, E, F]] {
+	r := newFuture[Tuple6[A, B, C, D, E, F]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple6[A, B, C, D, E, F]{a.v, b.v, c.v, d.v, e.v, f.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	joinFuture(j, d)
+	joinFuture(j, e)
+	joinFuture(j, f)
+	j.start()
+	return r
+}
+
+// Zip7 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip7[A, B, C, D, E, F, G any](a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], g *Future[G], opts ...Option) *Future[Tuple7[A, B, C, D, E, F, G]] {
+	r := newFuture[Tuple7[A, B, C, D, E, F, G]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple7[A, B, C, D, E, F, G]{a.v, b.v, c.v, d.v, e.v, f.v, g.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	joinFuture(j, d)
+	joinFuture(j, e)
+	joinFuture(j, f)
+	joinFuture(j, g)
+	j.start()
+	return r
+}
+
+// Zip8 returns a future that is resolved with a tuple of values of the given futures, once all of them are resolved. If any of the futures is rejected, the returned future is rejected with the same error. No goroutines are used for waiting.
+func Zip8[A, B, C, D, E, F, G, H any](a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], g *Future[G], h *Future[H], opts ...Option) *Future[Tuple8[A, B, C, D, E, F, G, H]] {
+	r := newFuture[Tuple8[A, B, C, D, E, F, G, H]](context.Background(), newOptions(opts), nil)
+	j := newJoiner(r.m, func() {
+		r.Resolve(Tuple8[A, B, C, D, E, F, G, H]{a.v, b.v, c.v, d.v, e.v, f.v, g.v, h.v})
+	}, r.reject)
+	joinFuture(j, a)
+	joinFuture(j, b)
+	joinFuture(j, c)
+	joinFuture(j, d)
+	joinFuture(j, e)
+	joinFuture(j, f)
+	joinFuture(j, g)
+	joinFuture(j, h)
+	j.start()
+	return r
+}
+
+// Join2 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join2[A, B, R any](ctx context.Context, a *Future[A], b *Future[B], fn func(A, B) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip2(a, b), func(t Tuple2[A, B]) (R, error) {
+		return fn(t.V1, t.V2)
+	}, opts)
+}
+
+// Join3 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join3[A, B, C, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], fn func(A, B, C) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip3(a, b, c), func(t Tuple3[A, B, C]) (R, error) {
+		return fn(t.V1, t.V2, t.V3)
+	}, opts)
+}
+
+// Join4 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join4[A, B, C, D, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], d *Future[D], fn func(A, B, C, D) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip4(a, b, c, d), func(t Tuple4[A, B, C, D]) (R, error) {
+		return fn(t.V1, t.V2, t.V3, t.V4)
+	}, opts)
+}
+
+// Join5 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join5[A, B, C, D, E, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], fn func(A, B, C, D, E) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip5(a, b, c, d, e), func(t Tuple5[A, B, C, D, E]) (R, error) {
+		return fn(t.V1, t.V2, t.V3, t.V4, t.V5)
+	}, opts)
+}
+
+// Join6 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join6[A, B, C, D, E, F, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], fn func(A, B, C, D, E, F) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip6(a, b, c, d, e, f), func(t Tuple6[A, B, C, D, E, F]) (R, error) {
+		return fn(t.V1, t.V2, t.V3, t.V4, t.V5, t.V6)
+	}, opts)
+}
+
+// Join7 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join7[A, B, C, D, E, F, G, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], g *Future[G], fn func(A, B, C, D, E, F, G) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip7(a, b, c, d, e, f, g), func(t Tuple7[A, B, C, D, E, F, G]) (R, error) {
+		return fn(t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7)
+	}, opts)
+}
+
+// Join8 returns a future that is resolved with the result of fn called with the values of the given futures, once all of them are resolved. The function is run using the configured executor (by default in a new goroutine). If any of the futures is rejected, fn is not called and the returned future is rejected with the same error. If the context is cancelled first, the returned future is rejected with the cause of the cancellation. If fn panics, the returned future is rejected with a *PanicError.
+func Join8[A, B, C, D, E, F, G, H, R any](ctx context.Context, a *Future[A], b *Future[B], c *Future[C], d *Future[D], e *Future[E], f *Future[F], g *Future[G], h *Future[H], fn func(A, B, C, D, E, F, G, H) (R, error), opts ...Option) *Future[R] {
+	return join(ctx, Zip8(a, b, c, d, e, f, g, h), func(t Tuple8[A, B, C, D, E, F, G, H]) (R, error) {
+		return fn(t.V1, t.V2, t.V3, t.V4, t.V5, t.V6, t.V7, t.V8)
+	}, opts)
+}
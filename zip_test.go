@@ -0,0 +1,92 @@
+package future_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daishe/go-future"
+	"github.com/daishe/go-future/futuretest"
+)
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := &future.Future[int]{}, &future.Future[string]{}, &future.Future[bool]{}
+	z := future.Zip3(a, b, c)
+
+	a.Resolve(1)
+	c.Resolve(true)
+	if futuretest.IsSuccessful(futuretest.GetResult(z, futuretest.IsDone)) {
+		t.Errorf("zip resolved before all futures were resolved")
+	}
+	b.Resolve("b")
+
+	x, y, w := z.Get().Unpack()
+	if x != 1 || y != "b" || w != true {
+		t.Errorf("zip resolved with (%v, %v, %v), expected (1, b, true)", x, y, w)
+	}
+}
+
+func TestZip8(t *testing.T) {
+	t.Parallel()
+
+	z := future.Zip8(future.Resolved(1), future.Resolved(int8(2)), future.Resolved(int16(3)), future.Resolved(int32(4)), future.Resolved(int64(5)), future.Resolved(uint(6)), future.Resolved(uint8(7)), future.Resolved("8"))
+	expected := future.Tuple8[int, int8, int16, int32, int64, uint, uint8, string]{1, 2, 3, 4, 5, 6, 7, "8"}
+	if got := z.Get(); got != expected {
+		t.Errorf("zip resolved with %v, expected %v", got, expected)
+	}
+}
+
+func TestZipRejected(t *testing.T) {
+	t.Parallel()
+
+	a, b := &future.Future[int]{}, &future.Future[string]{}
+	z := future.Zip2(a, b)
+	b.Reject(errTest)
+	if err := z.Err(); !errors.Is(err, errTest) {
+		t.Errorf("zip rejected with %v, expected %v", err, errTest)
+	}
+	a.Resolve(1)
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := &future.Future[int]{}, &future.Future[int]{}, &future.Future[string]{}, &future.Future[string]{}
+	got := future.Join4(t.Context(), a, b, c, d, func(a, b int, c, d string) (string, error) {
+		return c + d, nil
+	})
+	failed := future.Join2(t.Context(), a, c, func(int, string) (int, error) {
+		return 0, errTest
+	}, future.WithExecutor(future.Inline{}))
+
+	a.Resolve(1)
+	b.Resolve(2)
+	c.Resolve("c")
+	d.Resolve("d")
+
+	if v, err := got.Result(); v != "cd" || err != nil {
+		t.Errorf("join resolved with (%v, %v), expected (cd, nil)", v, err)
+	}
+	if err := failed.Err(); !errors.Is(err, errTest) {
+		t.Errorf("join rejected with %v, expected %v", err, errTest)
+	}
+}
+
+func TestJoinCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	a, b := &future.Future[int]{}, &future.Future[int]{}
+	got := future.Join2(ctx, a, b, func(a, b int) (int, error) {
+		t.Errorf("join function called after context cancel")
+		return a + b, nil
+	})
+	a.Resolve(1)
+	cancel()
+	if err := got.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("join rejected with %v, expected %v", err, context.Canceled)
+	}
+	b.Resolve(2)
+}